@@ -0,0 +1,90 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm identifies a hash function
+type Algorithm string
+
+// Supported hash functions
+const (
+	SHA256     Algorithm = "sha256"
+	SHA512     Algorithm = "sha512"
+	BLAKE2b256 Algorithm = "blake2b-256"
+	BLAKE2b512 Algorithm = "blake2b-512"
+)
+
+// Sums maps hash functions to the hex encoded digests they produced
+type Sums map[Algorithm]string
+
+var errUnknownAlgorithm = errors.New("unknown hash algorithm")
+
+// New returns a new hash.Hash computing the given algorithm
+func New(alg Algorithm) (hash.Hash, error) {
+	switch alg {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case BLAKE2b256:
+		return blake2b.New256(nil)
+	case BLAKE2b512:
+		return blake2b.New512(nil)
+	default:
+		return nil, errUnknownAlgorithm
+	}
+}
+
+// HashReader reads r until EOF and returns its digests for all given algorithms,
+// SHA-256 is used if no algorithm is given
+func HashReader(r io.Reader, algs ...Algorithm) (Sums, error) {
+	if len(algs) == 0 {
+		algs = []Algorithm{SHA256}
+	}
+
+	hashes := make(map[Algorithm]hash.Hash, len(algs))
+	writers := make([]io.Writer, 0, len(algs))
+	for _, alg := range algs {
+		if _, ok := hashes[alg]; ok {
+			continue
+		}
+		h, err := New(alg)
+		if err != nil {
+			return nil, err
+		}
+		hashes[alg] = h
+		writers = append(writers, h)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return nil, err
+	}
+
+	sums := make(Sums, len(hashes))
+	for alg, h := range hashes {
+		sums[alg] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	return sums, nil
+}
+
+// HashFile returns the digests of the file at path for all given algorithms,
+// SHA-256 is used if no algorithm is given
+func HashFile(path string, algs ...Algorithm) (Sums, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return HashReader(f, algs...)
+}
@@ -0,0 +1,155 @@
+package checksum
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHashReaderKnownAnswers(t *testing.T) {
+	tests := []struct {
+		alg        Algorithm
+		empty, abc string
+	}{
+		{
+			SHA256,
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		},
+		{
+			SHA512,
+			"cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+			"ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		},
+		{
+			BLAKE2b256,
+			"0e5751c026e543b2e8ab2eb06099daa1d1e5df47778f7787faab45cdf12fe3a8",
+			"bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		},
+		{
+			BLAKE2b512,
+			"786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce",
+			"ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.alg), func(t *testing.T) {
+			for input, want := range map[string]string{"": tt.empty, "abc": tt.abc} {
+				sums, err := HashReader(strings.NewReader(input), tt.alg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if sums[tt.alg] != want {
+					t.Fatalf("%q: got %s, want %s", input, sums[tt.alg], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHashReaderAlgorithms(t *testing.T) {
+	sums, err := HashReader(strings.NewReader("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != 1 || sums[SHA256] == "" {
+		t.Fatalf("expected SHA-256 by default, got %v", sums)
+	}
+
+	sums, err = HashReader(strings.NewReader("abc"), SHA256, BLAKE2b512, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sums) != 2 || sums[SHA256] == "" || sums[BLAKE2b512] == "" {
+		t.Fatalf("got %v", sums)
+	}
+
+	if _, err := HashReader(strings.NewReader("abc"), "md5"); !errors.Is(err, errUnknownAlgorithm) {
+		t.Fatalf("expected errUnknownAlgorithm, got %v", err)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"a.txt": "abc", "sub/b.txt": ""}
+	for p, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var manifest strings.Builder
+	if err := WriteManifest(&manifest, dir, []string{"a.txt", filepath.FromSlash("sub/b.txt")}, SHA256); err != nil {
+		t.Fatal(err)
+	}
+	want := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  a.txt\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  sub/b.txt\n"
+	if manifest.String() != want {
+		t.Fatalf("got\n%s", manifest.String())
+	}
+
+	manifestPath := filepath.Join(dir, "SHA256SUMS")
+	if err := os.WriteFile(manifestPath, []byte(manifest.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if mismatches, err := VerifyManifest(manifestPath, SHA256); err != nil || len(mismatches) != 0 {
+		t.Fatalf("intact tree: %v %v", mismatches, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abd"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	mismatches, err := VerifyManifest(manifestPath, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mismatches) != 2 || mismatches[0].Path != "a.txt" || mismatches[0].Actual == "" ||
+		mismatches[1].Path != "sub/b.txt" || !errors.Is(mismatches[1].Err, os.ErrNotExist) {
+		t.Fatalf("got %+v", mismatches)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     map[string]string
+		err      bool
+	}{
+		{
+			name:     "text and binary mode",
+			manifest: "# comment\nABCD  a.txt\r\n\nef01 *b c.bin\n",
+			want:     map[string]string{"a.txt": "abcd", "b c.bin": "ef01"},
+		},
+		{name: "no separator", manifest: "abcd\n", err: true},
+		{name: "no path", manifest: "abcd \n", err: true},
+		{name: "leading space", manifest: " abcd  a.txt\n", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseManifest(strings.NewReader(tt.manifest))
+			if tt.err {
+				if !errors.Is(err, errManifestLine) {
+					t.Fatalf("expected errManifestLine, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
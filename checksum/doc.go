@@ -0,0 +1,7 @@
+/*
+Package checksum computes and verifies file and stream digests.
+
+It supports SHA-256, SHA-512, and BLAKE2b, can compute several digests in a single pass,
+and verifies files against manifests in the format written by sha256sum and friends.
+*/
+package checksum
@@ -0,0 +1,78 @@
+package checksum
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// SipHash24 is the keyed SipHash-2-4 function, only available through the keyed APIs
+const SipHash24 Algorithm = "siphash-2-4"
+
+var errNotKeyed = errors.New("hash algorithm does not support keys")
+
+// NewKeyed returns a new keyed hash.Hash computing the given algorithm.
+// BLAKE2b accepts keys of up to 64 bytes, SipHash requires exactly 16 bytes.
+func NewKeyed(alg Algorithm, key []byte) (hash.Hash, error) {
+	switch alg {
+	case BLAKE2b256:
+		return blake2b.New256(key)
+	case BLAKE2b512:
+		return blake2b.New512(key)
+	case SipHash24:
+		return NewSipHash(key)
+	default:
+		return nil, errNotKeyed
+	}
+}
+
+// GenerateKey returns a random key of the recommended size for the given keyed algorithm
+func GenerateKey(alg Algorithm) ([]byte, error) {
+	var size int
+	switch alg {
+	case BLAKE2b256:
+		size = blake2b.Size256
+	case BLAKE2b512:
+		size = blake2b.Size
+	case SipHash24:
+		size = SipHashKeySize
+	default:
+		return nil, errNotKeyed
+	}
+
+	key := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// MACReader reads r until EOF and returns the hex encoded keyed digest of the given algorithm
+func MACReader(r io.Reader, alg Algorithm, key []byte) (string, error) {
+	h, err := NewKeyed(alg, key)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// VerifyMAC reports in constant time whether mac is the keyed digest of data
+func VerifyMAC(alg Algorithm, key, data, mac []byte) (bool, error) {
+	h, err := NewKeyed(alg, key)
+	if err != nil {
+		return false, err
+	}
+	_, _ = h.Write(data)
+
+	return subtle.ConstantTimeCompare(h.Sum(nil), mac) == 1, nil
+}
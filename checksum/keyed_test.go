@@ -0,0 +1,134 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// sequence returns the bytes 0, 1, ..., n-1 as used by the reference test vectors
+func sequence(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+
+	return b
+}
+
+func TestSipHashKnownAnswers(t *testing.T) {
+	// vectors_sip64 of the SipHash reference implementation, key 00..0f and input 00..n-1
+	tests := []struct {
+		n    int
+		want uint64
+	}{
+		{0, 0x726fdb47dd0e0e31},
+		{1, 0x74f839c593dc67fd},
+		{2, 0x0d6c8009d9a94f5a},
+		{3, 0x85676696d7fb7e2d},
+		{7, 0xab0200f58b01d137},
+		{8, 0x93f5f5799a932462},
+		{15, 0xa129ca6149be45e5},
+		{16, 0x3f2acc7f57c29bdb},
+		{63, 0x958a324ceb064572},
+	}
+	var key [SipHashKeySize]byte
+	copy(key[:], sequence(SipHashKeySize))
+
+	for _, tt := range tests {
+		msg := sequence(tt.n)
+		if got := SipHash64(key, msg); got != tt.want {
+			t.Fatalf("%d bytes: got %#x, want %#x", tt.n, got, tt.want)
+		}
+
+		// writes split at every position give the same result
+		h, err := NewSipHash(key[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for split := 0; split <= tt.n; split++ {
+			h.Reset()
+			h.Write(msg[:split])
+			h.Write(msg[split:])
+			if got := h.Sum64(); got != tt.want {
+				t.Fatalf("%d bytes split at %d: got %#x", tt.n, split, got)
+			}
+		}
+	}
+}
+
+func TestSipHashSumLittleEndian(t *testing.T) {
+	h, err := NewKeyed(SipHash24, sequence(SipHashKeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Write(sequence(15))
+	if got := hex.EncodeToString(h.Sum(nil)); got != "e545be4961ca29a1" {
+		t.Fatalf("got %s", got)
+	}
+
+	// Sum does not finalize the state
+	h.Write([]byte{15})
+	if got := hex.EncodeToString(h.Sum(nil)); got != "db9bc2577fcc2a3f" {
+		t.Fatalf("got %s after another write", got)
+	}
+
+	if _, err := NewSipHash(make([]byte, 15)); !errors.Is(err, errSipHashKey) {
+		t.Fatalf("expected errSipHashKey, got %v", err)
+	}
+}
+
+func TestKeyedBLAKE2b(t *testing.T) {
+	// blake2b-kat.txt of the BLAKE2 reference implementation, key 00..3f
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "10ebb67700b1868efb4417987acf4690ae9d972fb7a590c2f02871799aaa4786b5e996e8f0f4eb981fc214b005f42d2ff4233499391653df7aefcbc13fc51568"},
+		{3, "33d0825dddf7ada99b0e7e307104ad07ca9cfd9692214f1561356315e784f3e5a17e364ae9dbb14cb2036df932b77f4b292761365fb328de7afdc6d8998f5fc1"},
+	}
+	key := sequence(64)
+	for _, tt := range tests {
+		got, err := MACReader(bytes.NewReader(sequence(tt.n)), BLAKE2b512, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("%d bytes: got %s", tt.n, got)
+		}
+
+		mac, _ := hex.DecodeString(tt.want)
+		if ok, err := VerifyMAC(BLAKE2b512, key, sequence(tt.n), mac); err != nil || !ok {
+			t.Fatalf("%d bytes: VerifyMAC rejected the MAC: %v", tt.n, err)
+		}
+		mac[0] ^= 1
+		if ok, _ := VerifyMAC(BLAKE2b512, key, sequence(tt.n), mac); ok {
+			t.Fatalf("%d bytes: VerifyMAC accepted a modified MAC", tt.n)
+		}
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	tests := []struct {
+		alg  Algorithm
+		size int
+		err  error
+	}{
+		{BLAKE2b256, 32, nil},
+		{BLAKE2b512, 64, nil},
+		{SipHash24, SipHashKeySize, nil},
+		{SHA256, 0, errNotKeyed},
+	}
+	for _, tt := range tests {
+		key, err := GenerateKey(tt.alg)
+		if !errors.Is(err, tt.err) || len(key) != tt.size {
+			t.Fatalf("%s: got %d bytes, %v", tt.alg, len(key), err)
+		}
+		if err == nil {
+			if _, err := NewKeyed(tt.alg, key); err != nil {
+				t.Fatalf("%s: generated key rejected: %v", tt.alg, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package checksum
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var errManifestLine = errors.New("malformed manifest line")
+
+// Mismatch describes a manifest entry that failed verification,
+// Err is set if the file could not be hashed at all
+type Mismatch struct {
+	Path     string
+	Expected string
+	Actual   string
+	Err      error
+}
+
+// WriteManifest writes the digests of the given files, relative to dir,
+// in the '<hex>  <path>' format of sha256sum and friends
+func WriteManifest(w io.Writer, dir string, paths []string, alg Algorithm) error {
+	for _, p := range paths {
+		sums, err := HashFile(filepath.Join(dir, p), alg)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s  %s\n", sums[alg], filepath.ToSlash(p)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseManifest reads a manifest in the '<hex>  <path>' format of sha256sum and friends
+// and returns the expected digests keyed by path
+func ParseManifest(r io.Reader) (map[string]string, error) {
+	entries := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		i := strings.IndexByte(text, ' ')
+		if i <= 0 || i+2 > len(text) {
+			return nil, fmt.Errorf("line %d: %w", line, errManifestLine)
+		}
+		// the second separator character is either ' ' (text mode) or '*' (binary mode)
+		entries[text[i+2:]] = strings.ToLower(text[:i])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// VerifyManifest checks all files listed in the manifest at manifestPath, relative to the
+// manifest's directory, against their expected digests and returns the entries that failed
+func VerifyManifest(manifestPath string, alg Algorithm) ([]Mismatch, error) {
+	f, err := os.Open(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := ParseManifest(f)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(manifestPath)
+	var mismatches []Mismatch
+	for p, expected := range entries {
+		sums, err := HashFile(filepath.Join(dir, filepath.FromSlash(p)), alg)
+		if err != nil {
+			mismatches = append(mismatches, Mismatch{Path: p, Expected: expected, Err: err})
+			continue
+		}
+		if sums[alg] != expected {
+			mismatches = append(mismatches, Mismatch{Path: p, Expected: expected, Actual: sums[alg]})
+		}
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Path < mismatches[j].Path
+	})
+
+	return mismatches, nil
+}
@@ -0,0 +1,130 @@
+package checksum
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// SipHashKeySize is the key size of SipHash in bytes
+const SipHashKeySize = 16
+
+var errSipHashKey = errors.New("siphash requires a 16 byte key")
+
+type sipHash struct {
+	k0, k1         uint64
+	v0, v1, v2, v3 uint64
+	buf            [8]byte
+	n              int
+	length         uint64
+}
+
+// NewSipHash returns a streaming SipHash-2-4 hash.Hash64 using the given 16 byte key
+func NewSipHash(key []byte) (hash.Hash64, error) {
+	if len(key) != SipHashKeySize {
+		return nil, errSipHashKey
+	}
+
+	h := &sipHash{
+		k0: binary.LittleEndian.Uint64(key[:8]),
+		k1: binary.LittleEndian.Uint64(key[8:]),
+	}
+	h.Reset()
+
+	return h, nil
+}
+
+// SipHash64 returns the SipHash-2-4 of data using the given 16 byte key,
+// a fast keyed hash resistant to hash-flooding when used for table keys
+func SipHash64(key [SipHashKeySize]byte, data []byte) uint64 {
+	h, _ := NewSipHash(key[:])
+	_, _ = h.Write(data)
+
+	return h.Sum64()
+}
+
+func (h *sipHash) Reset() {
+	h.v0 = h.k0 ^ 0x736f6d6570736575
+	h.v1 = h.k1 ^ 0x646f72616e646f6d
+	h.v2 = h.k0 ^ 0x6c7967656e657261
+	h.v3 = h.k1 ^ 0x7465646279746573
+	h.n = 0
+	h.length = 0
+}
+
+func (h *sipHash) Size() int { return 8 }
+
+func (h *sipHash) BlockSize() int { return 8 }
+
+func (h *sipHash) Write(p []byte) (int, error) {
+	written := len(p)
+	h.length += uint64(written)
+
+	if h.n > 0 {
+		c := copy(h.buf[h.n:], p)
+		h.n += c
+		p = p[c:]
+		if h.n < 8 {
+			return written, nil
+		}
+		h.block(binary.LittleEndian.Uint64(h.buf[:]))
+		h.n = 0
+	}
+
+	for len(p) >= 8 {
+		h.block(binary.LittleEndian.Uint64(p))
+		p = p[8:]
+	}
+	h.n = copy(h.buf[:], p)
+
+	return written, nil
+}
+
+func (h *sipHash) Sum(b []byte) []byte {
+	var out [8]byte
+	binary.LittleEndian.PutUint64(out[:], h.Sum64())
+
+	return append(b, out[:]...)
+}
+
+func (h *sipHash) Sum64() uint64 {
+	// finalize on a copy so the hash can keep being written to
+	d := *h
+
+	var last [8]byte
+	copy(last[:], d.buf[:d.n])
+	last[7] = byte(d.length)
+	d.block(binary.LittleEndian.Uint64(last[:]))
+
+	d.v2 ^= 0xff
+	for i := 0; i < 4; i++ {
+		d.round()
+	}
+
+	return d.v0 ^ d.v1 ^ d.v2 ^ d.v3
+}
+
+func (h *sipHash) block(m uint64) {
+	h.v3 ^= m
+	h.round()
+	h.round()
+	h.v0 ^= m
+}
+
+func (h *sipHash) round() {
+	h.v0 += h.v1
+	h.v1 = bits.RotateLeft64(h.v1, 13)
+	h.v1 ^= h.v0
+	h.v0 = bits.RotateLeft64(h.v0, 32)
+	h.v2 += h.v3
+	h.v3 = bits.RotateLeft64(h.v3, 16)
+	h.v3 ^= h.v2
+	h.v0 += h.v3
+	h.v3 = bits.RotateLeft64(h.v3, 21)
+	h.v3 ^= h.v0
+	h.v2 += h.v1
+	h.v1 = bits.RotateLeft64(h.v1, 17)
+	h.v1 ^= h.v2
+	h.v2 = bits.RotateLeft64(h.v2, 32)
+}
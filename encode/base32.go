@@ -0,0 +1,77 @@
+package encode
+
+import (
+	"strings"
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Crockford32 encodes data with Crockford's base32 alphabet, without padding
+func Crockford32(data []byte) string {
+	var out strings.Builder
+	out.Grow((len(data)*8 + 4) / 5)
+
+	var acc uint
+	var bits uint
+	for _, b := range data {
+		acc = acc<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			out.WriteByte(crockfordAlphabet[acc>>bits&0x1f])
+		}
+	}
+	if bits > 0 {
+		out.WriteByte(crockfordAlphabet[acc<<(5-bits)&0x1f])
+	}
+
+	return out.String()
+}
+
+// DecodeCrockford32 decodes a Crockford base32 string. Decoding is case-insensitive,
+// maps 'I' and 'L' to '1' and 'O' to '0', and ignores hyphens used for grouping.
+func DecodeCrockford32(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s)*5/8)
+
+	var acc uint
+	var bits uint
+	for i := 0; i < len(s); i++ {
+		v, ok := crockfordValue(s[i])
+		if !ok {
+			return nil, errChar
+		}
+		if v < 0 {
+			continue
+		}
+
+		acc = acc<<5 | uint(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return nil, errLength
+	}
+
+	return out, nil
+}
+
+// crockfordValue returns the value of c, -1 for ignored characters, and false for invalid ones
+func crockfordValue(c byte) (int, bool) {
+	switch {
+	case c == '-':
+		return -1, true
+	case c == 'O' || c == 'o':
+		return 0, true
+	case c == 'I' || c == 'i' || c == 'L' || c == 'l':
+		return 1, true
+	case c >= 'a' && c <= 'z':
+		c -= 'a' - 'A'
+	}
+
+	i := strings.IndexByte(crockfordAlphabet, c)
+
+	return i, i >= 0
+}
@@ -0,0 +1,59 @@
+package encode
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestCrockford32(t *testing.T) {
+	tests := []struct {
+		data    []byte
+		encoded string
+	}{
+		{[]byte{}, ""},
+		{[]byte("f"), "CR"},
+		{[]byte("fo"), "CSQG"},
+		{[]byte("foo"), "CSQPY"},
+		{[]byte("foobar"), "CSQPYRK1E8"},
+		{[]byte{0xff, 0xff, 0xff, 0xff, 0xff}, "ZZZZZZZZ"},
+	}
+	for _, tt := range tests {
+		if got := Crockford32(tt.data); got != tt.encoded {
+			t.Fatalf("%x: got %q, want %q", tt.data, got, tt.encoded)
+		}
+		got, err := DecodeCrockford32(tt.encoded)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.encoded, err)
+		}
+		if !bytes.Equal(got, tt.data) {
+			t.Fatalf("%q: got %x, want %x", tt.encoded, got, tt.data)
+		}
+	}
+}
+
+func TestDecodeCrockford32(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+		err  error
+	}{
+		{"csqpy", "foo", nil},
+		{"CSQ-PYR-K1E8", "foobar", nil},
+		{"CSQPYRKIE8", "foobar", nil},
+		{"CSQPYRKlE8", "foobar", nil},
+		{"0O", "\x00", nil},
+		{"CSQPU", "", errChar},
+		{"CSQP*", "", errChar},
+		// leftover bits must be zero
+		{"CS", "", errLength},
+		// a lone character cannot hold a byte
+		{"CSQPY0", "", errLength},
+	}
+	for _, tt := range tests {
+		got, err := DecodeCrockford32(tt.s)
+		if !errors.Is(err, tt.err) || (err == nil && string(got) != tt.want) {
+			t.Fatalf("%q: got %q, %v", tt.s, got, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package encode
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	errChar     = errors.New("input contains an invalid character")
+	errChecksum = errors.New("checksum mismatch")
+	errLength   = errors.New("input has an invalid length")
+)
+
+var base58Index = func() [256]int8 {
+	var idx [256]int8
+	for i := range idx {
+		idx[i] = -1
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		idx[base58Alphabet[i]] = int8(i)
+	}
+
+	return idx
+}()
+
+// Base58 encodes data with the Bitcoin base58 alphabet, preserving leading zero bytes as '1'
+func Base58(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	b58 := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, b58, mod)
+		out = append(out, base58Alphabet[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, '1')
+	}
+
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return string(out)
+}
+
+// DecodeBase58 decodes a base58 string
+func DecodeBase58(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '1' {
+		zeros++
+	}
+
+	n := new(big.Int)
+	b58 := big.NewInt(58)
+	for i := zeros; i < len(s); i++ {
+		v := base58Index[s[i]]
+		if v < 0 {
+			return nil, errChar
+		}
+		n.Mul(n, b58)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+// Base58Check encodes version and payload as base58 with a 4 byte double SHA-256 checksum appended
+func Base58Check(version byte, payload []byte) string {
+	data := make([]byte, 0, len(payload)+5)
+	data = append(data, version)
+	data = append(data, payload...)
+
+	return Base58(append(data, doubleSHA256(data)[:4]...))
+}
+
+// DecodeBase58Check decodes a Base58Check string and verifies its checksum
+func DecodeBase58Check(s string) (version byte, payload []byte, err error) {
+	data, err := DecodeBase58(s)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 5 {
+		return 0, nil, errLength
+	}
+
+	body, checksum := data[:len(data)-4], data[len(data)-4:]
+	if !bytes.Equal(doubleSHA256(body)[:4], checksum) {
+		return 0, nil, errChecksum
+	}
+
+	return body[0], body[1:], nil
+}
+
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+
+	return second[:]
+}
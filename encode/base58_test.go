@@ -0,0 +1,70 @@
+package encode
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	// examples of the base58 encoding scheme draft, draft-msporny-base58
+	tests := []struct {
+		data    []byte
+		encoded string
+	}{
+		{[]byte{}, ""},
+		{[]byte("Hello World!"), "2NEpo7TZRRrLZSi2U"},
+		{[]byte("The quick brown fox jumps over the lazy dog."), "USm3fpXnKG5EUBx2ndxBDMPVciP5hGey2Jh4NDv6gmeo1LkMeiKrLJUUBk6Z"},
+		{[]byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}, "11233QC4"},
+		{[]byte{0x00}, "1"},
+	}
+	for _, tt := range tests {
+		if got := Base58(tt.data); got != tt.encoded {
+			t.Fatalf("%x: got %q, want %q", tt.data, got, tt.encoded)
+		}
+		got, err := DecodeBase58(tt.encoded)
+		if err != nil {
+			t.Fatalf("%q: %v", tt.encoded, err)
+		}
+		if !bytes.Equal(got, tt.data) {
+			t.Fatalf("%q: got %x, want %x", tt.encoded, got, tt.data)
+		}
+	}
+
+	for _, s := range []string{"0", "O", "I", "l", "2NEpo7TZRRrLZSi2U!"} {
+		if _, err := DecodeBase58(s); !errors.Is(err, errChar) {
+			t.Fatalf("%q: expected errChar, got %v", s, err)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	tests := []struct {
+		version byte
+		payload string
+		encoded string
+	}{
+		{0, "0000000000000000000000000000000000000000", "1111111111111111111114oLvT2"},
+		// the Bitcoin wiki's technical background of version 1 addresses
+		{0, "010966776006953d5567439e5e39f86a0d273bee", "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"},
+	}
+	for _, tt := range tests {
+		payload, _ := hex.DecodeString(tt.payload)
+		if got := Base58Check(tt.version, payload); got != tt.encoded {
+			t.Fatalf("got %q, want %q", got, tt.encoded)
+		}
+		version, got, err := DecodeBase58Check(tt.encoded)
+		if err != nil || version != tt.version || !bytes.Equal(got, payload) {
+			t.Fatalf("%q: got %d %x, %v", tt.encoded, version, got, err)
+		}
+	}
+
+	// a single changed character breaks the checksum
+	if _, _, err := DecodeBase58Check("16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN"); !errors.Is(err, errChecksum) {
+		t.Fatalf("expected errChecksum, got %v", err)
+	}
+	if _, _, err := DecodeBase58Check("1111"); !errors.Is(err, errLength) {
+		t.Fatalf("expected errLength, got %v", err)
+	}
+}
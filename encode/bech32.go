@@ -0,0 +1,151 @@
+package encode
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	bech32Alphabet  = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32MaxLength = 90
+)
+
+var (
+	errHRP  = errors.New("bech32 human readable part is invalid")
+	errCase = errors.New("bech32 string mixes upper and lower case")
+)
+
+// Bech32 encodes data with the human readable part hrp as BIP 173 Bech32 string.
+// Bech32 strings are limited to 90 characters, which fits payloads of up to 50 bytes
+// depending on the length of hrp, e.g. a SHA-256 fingerprint.
+func Bech32(hrp string, data []byte) (string, error) {
+	if hrp == "" || strings.ToLower(hrp) != hrp {
+		return "", errHRP
+	}
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", errHRP
+		}
+	}
+
+	values := convertBits(data, 8, 5, true)
+	checksum := bech32Checksum(hrp, values)
+	if len(hrp)+1+len(values)+len(checksum) > bech32MaxLength {
+		return "", errLength
+	}
+
+	var out strings.Builder
+	out.WriteString(hrp)
+	out.WriteByte('1')
+	for _, v := range append(values, checksum...) {
+		out.WriteByte(bech32Alphabet[v])
+	}
+
+	return out.String(), nil
+}
+
+// DecodeBech32 decodes a BIP 173 Bech32 string and verifies its checksum
+func DecodeBech32(s string) (hrp string, data []byte, err error) {
+	if len(s) > bech32MaxLength {
+		return "", nil, errLength
+	}
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, errCase
+	}
+
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, errLength
+	}
+	hrp = lower[:sep]
+
+	values := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(bech32Alphabet, lower[i])
+		if v < 0 {
+			return "", nil, errChar
+		}
+		values = append(values, byte(v))
+	}
+
+	if bech32Polymod(append(bech32ExpandHRP(hrp), values...)) != 1 {
+		return "", nil, errChecksum
+	}
+
+	data = convertBits(values[:len(values)-6], 5, 8, false)
+	if data == nil {
+		return "", nil, errLength
+	}
+
+	return hrp, data, nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if top>>i&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func bech32ExpandHRP(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+
+	return out
+}
+
+func bech32Checksum(hrp string, values []byte) []byte {
+	polymod := bech32Polymod(append(append(bech32ExpandHRP(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(polymod >> (5 * (5 - i)) & 31)
+	}
+
+	return checksum
+}
+
+// convertBits regroups data from groups of from bits into groups of to bits,
+// it returns nil if pad is false and the input has leftover non-zero bits
+func convertBits(data []byte, from, to uint, pad bool) []byte {
+	var acc uint
+	var bits uint
+	maxv := uint(1)<<to - 1
+
+	out := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil
+	}
+
+	return out
+}
@@ -0,0 +1,91 @@
+package encode
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBech32Vectors(t *testing.T) {
+	// valid Bech32 strings of BIP 173 whose data is a whole number of bytes
+	tests := []struct {
+		s    string
+		hrp  string
+		data string
+	}{
+		{"A12UEL5L", "a", ""},
+		{"a12uel5l", "a", ""},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", "abcdef", "00443214c74254b635cf84653a56d7c675be77df"},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", "split", ""},
+	}
+	for _, tt := range tests {
+		hrp, data, err := DecodeBech32(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		}
+		if hrp != tt.hrp || (tt.data != "" && hex.EncodeToString(data) != tt.data) {
+			t.Fatalf("%s: got %s %x", tt.s, hrp, data)
+		}
+
+		encoded, err := Bech32(hrp, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != strings.ToLower(tt.s) {
+			t.Fatalf("%x: got %s, want %s", data, encoded, strings.ToLower(tt.s))
+		}
+	}
+}
+
+func TestDecodeBech32Errors(t *testing.T) {
+	// invalid Bech32 strings of BIP 173
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", errLength},
+		{"pzry9x0s0muk", errLength},
+		{"1pzry9x0s0muk", errLength},
+		{"x1b4n0q5v", errChar},
+		{"li1dgmt3", errLength},
+		{"A1G7SGD8", errChecksum},
+		{"10a06t8", errLength},
+		{"1qzzfhee", errLength},
+		{"a12UEL5L", errCase},
+	}
+	for _, tt := range tests {
+		if _, _, err := DecodeBech32(tt.s); !errors.Is(err, tt.err) {
+			t.Fatalf("%s: expected %v, got %v", tt.s, tt.err, err)
+		}
+	}
+}
+
+func TestBech32RoundTrip(t *testing.T) {
+	fingerprint := bytes.Repeat([]byte{0xa5}, 32)
+	encoded, err := Bech32("rsakys", fingerprint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hrp, data, err := DecodeBech32(strings.ToUpper(encoded))
+	if err != nil || hrp != "rsakys" || !bytes.Equal(data, fingerprint) {
+		t.Fatalf("got %s %x, %v", hrp, data, err)
+	}
+
+	tests := []struct {
+		hrp  string
+		data []byte
+		err  error
+	}{
+		{"", nil, errHRP},
+		{"Upper", nil, errHRP},
+		{"a b", nil, errHRP},
+		{"rsakys", make([]byte, 51), errLength},
+	}
+	for _, tt := range tests {
+		if _, err := Bech32(tt.hrp, tt.data); !errors.Is(err, tt.err) {
+			t.Fatalf("%q: expected %v, got %v", tt.hrp, tt.err, err)
+		}
+	}
+}
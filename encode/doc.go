@@ -0,0 +1,8 @@
+/*
+Package encode provides compact text encodings for fingerprints and public key material.
+
+Base58Check adds a version byte and a double SHA-256 checksum to base58, Crockford base32 is
+case-insensitive and avoids ambiguous characters, and Bech32 adds a human readable prefix and a
+BCH checksum that detects typos, which makes them suitable for short share codes and hardware labels.
+*/
+package encode
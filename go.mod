@@ -1,3 +1,16 @@
 module github.com/abecodes/goutls
 
-go 1.17
+go 1.22.0
+
+require (
+	filippo.io/bigmod v0.0.3
+	github.com/skip2/go-qrcode v0.0.0-20200617195104-da1b6568686e
+	golang.org/x/crypto v0.33.0
+	google.golang.org/grpc v1.71.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	golang.org/x/sys v0.30.0 // indirect
+	google.golang.org/protobuf v1.36.4 // indirect
+)
@@ -0,0 +1,7 @@
+/*
+Package grpccreds builds gRPC transport credentials from key, certificate, and CA files.
+
+Servers and clients get TLS or mutual TLS in one call, certificates replaced on disc
+are picked up on the next handshake without a restart.
+*/
+package grpccreds
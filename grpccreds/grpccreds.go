@@ -0,0 +1,28 @@
+package grpccreds
+
+import (
+	"github.com/abecodes/goutls/rsakys"
+	"google.golang.org/grpc/credentials"
+)
+
+// Server returns the transport credentials of a gRPC server for the certificate and RSA key
+// in certPath and keyPath. If caPath is set, clients must authenticate with a certificate of one of its CAs.
+func Server(certPath, keyPath, caPath string) (credentials.TransportCredentials, error) {
+	cfg, err := rsakys.ServerTLSConfig(certPath, keyPath, caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// Client returns the transport credentials of a gRPC client trusting the CAs in caPath,
+// or the system roots if it is empty. If certPath and keyPath are set, the client authenticates with them.
+func Client(certPath, keyPath, caPath string) (credentials.TransportCredentials, error) {
+	cfg, err := rsakys.ClientTLSConfig(certPath, keyPath, caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
@@ -0,0 +1,10 @@
+/*
+Package id generates unique identifiers.
+
+ULIDs combine a millisecond timestamp with 80 random bits and encode to 26 Crockford base32 characters,
+KSUIDs combine a second timestamp with 128 random bits and encode to 27 base62 characters.
+Both sort lexicographically by creation time, and the monotonic generators additionally keep
+identifiers created within the same timestamp strictly increasing.
+RFC 9562 UUIDs are available as random version 4 and time-ordered version 7.
+*/
+package id
@@ -0,0 +1,28 @@
+package id
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+var (
+	errLength   = errors.New("id has an invalid length")
+	errChar     = errors.New("id contains an invalid character")
+	errOverflow = errors.New("id value overflows")
+)
+
+// entropy is the source of all random id parts
+var entropy io.Reader = rand.Reader
+
+// increment adds one to the big endian number in b and reports whether it overflowed
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return false
+		}
+	}
+
+	return true
+}
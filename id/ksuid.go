@@ -0,0 +1,118 @@
+package id
+
+import (
+	"encoding/binary"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ksuidEpoch       = 1400000000
+	ksuidEncodedSize = 27
+	base62           = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// KSUID is a 160 bit identifier made of a 32 bit timestamp in seconds since 2014-05-13 and 128 random bits
+type KSUID [20]byte
+
+var ksuidMax = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+
+// NewKSUID returns a KSUID for the current time
+func NewKSUID() (KSUID, error) {
+	return newKSUID(time.Now())
+}
+
+func newKSUID(t time.Time) (KSUID, error) {
+	var k KSUID
+	binary.BigEndian.PutUint32(k[:4], uint32(t.Unix()-ksuidEpoch))
+	if _, err := io.ReadFull(entropy, k[4:]); err != nil {
+		return KSUID{}, err
+	}
+
+	return k, nil
+}
+
+// ParseKSUID decodes the 27 character base62 form of a KSUID
+func ParseKSUID(s string) (KSUID, error) {
+	if len(s) != ksuidEncodedSize {
+		return KSUID{}, errLength
+	}
+
+	n := new(big.Int)
+	b62 := big.NewInt(62)
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(base62, s[i])
+		if v < 0 {
+			return KSUID{}, errChar
+		}
+		n.Mul(n, b62)
+		n.Add(n, big.NewInt(int64(v)))
+	}
+	if n.Cmp(ksuidMax) > 0 {
+		return KSUID{}, errOverflow
+	}
+
+	var k KSUID
+	n.FillBytes(k[:])
+
+	return k, nil
+}
+
+// String returns the 27 character base62 form of the KSUID
+func (k KSUID) String() string {
+	out := make([]byte, ksuidEncodedSize)
+
+	n := new(big.Int).SetBytes(k[:])
+	b62 := big.NewInt(62)
+	mod := new(big.Int)
+	for i := len(out) - 1; i >= 0; i-- {
+		n.DivMod(n, b62, mod)
+		out[i] = base62[mod.Int64()]
+	}
+
+	return string(out)
+}
+
+// Time returns the timestamp of the KSUID
+func (k KSUID) Time() time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(k[:4]))+ksuidEpoch, 0)
+}
+
+// MonotonicKSUID generates KSUIDs that strictly increase, even within the same second,
+// by incrementing the random part of the previous KSUID instead of drawing new randomness
+type MonotonicKSUID struct {
+	mu   sync.Mutex
+	last KSUID
+}
+
+// NewMonotonicKSUID creates a monotonic KSUID generator
+func NewMonotonicKSUID() *MonotonicKSUID {
+	return &MonotonicKSUID{}
+}
+
+// New returns the next KSUID
+func (g *MonotonicKSUID) New() (KSUID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if now.Unix() > g.last.Time().Unix() {
+		k, err := newKSUID(now)
+		if err != nil {
+			return KSUID{}, err
+		}
+		g.last = k
+		return k, nil
+	}
+
+	next := g.last
+	if increment(next[4:]) {
+		return KSUID{}, errOverflow
+	}
+	g.last = next
+
+	return next, nil
+}
@@ -0,0 +1,78 @@
+package id
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKSUIDEncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+	}{
+		{"000000000000000000000000000", "0000000000000000000000000000000000000000"},
+		// example of the segmentio/ksuid README
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLOv", "0669f7efb5a1cd34b5f99d1154fb6853345c9735"},
+		{"aWgEPTl1tmebfsQzFP4bxwgy80V", "ffffffffffffffffffffffffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		k, err := ParseKSUID(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		}
+		if got := hex.EncodeToString(k[:]); got != tt.hex {
+			t.Fatalf("%s: got %s, want %s", tt.s, got, tt.hex)
+		}
+		if k.String() != tt.s {
+			t.Fatalf("%s: encoded as %s", tt.s, k)
+		}
+	}
+
+	k, _ := ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if got := k.Time().Unix(); got != 107608047+ksuidEpoch {
+		t.Fatalf("got time %d", got)
+	}
+}
+
+func TestParseKSUIDErrors(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLO", errLength},
+		{"0ujtsYcgvSTl8PAuAdqWYSMnLO-", errChar},
+		{"aWgEPTl1tmebfsQzFP4bxwgy80W", errOverflow},
+		{"zzzzzzzzzzzzzzzzzzzzzzzzzzz", errOverflow},
+	}
+	for _, tt := range tests {
+		if _, err := ParseKSUID(tt.s); !errors.Is(err, tt.err) {
+			t.Fatalf("%s: expected %v, got %v", tt.s, tt.err, err)
+		}
+	}
+}
+
+func TestMonotonicKSUID(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	g := NewMonotonicKSUID()
+	prev, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev.Time().Before(now) {
+		t.Fatalf("time %v is not the current time", prev.Time())
+	}
+	for i := 0; i < 1000; i++ {
+		next, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(next[:], prev[:]) <= 0 || strings.Compare(next.String(), prev.String()) <= 0 {
+			t.Fatalf("%s does not follow %s", next, prev)
+		}
+		prev = next
+	}
+}
@@ -0,0 +1,151 @@
+package id
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ulidEncodedSize = 26
+	crockford       = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// ULID is a 128 bit identifier made of a 48 bit Unix millisecond timestamp and 80 random bits
+type ULID [16]byte
+
+// NewULID returns a ULID for the current time
+func NewULID() (ULID, error) {
+	return newULID(time.Now())
+}
+
+func newULID(t time.Time) (ULID, error) {
+	var u ULID
+	u.setTime(t)
+	if _, err := io.ReadFull(entropy, u[6:]); err != nil {
+		return ULID{}, err
+	}
+
+	return u, nil
+}
+
+// ParseULID decodes the 26 character Crockford base32 form of a ULID, case-insensitively
+func ParseULID(s string) (ULID, error) {
+	if len(s) != ulidEncodedSize {
+		return ULID{}, errLength
+	}
+
+	var u ULID
+	var acc uint
+	var bits uint
+	pos := 0
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(crockford, upper(s[i]))
+		if v < 0 {
+			return ULID{}, errChar
+		}
+		if i == 0 && v > 7 {
+			return ULID{}, errOverflow
+		}
+
+		acc = acc<<5 | uint(v)
+		bits += 5
+		if i == 0 {
+			// the first character only carries 3 bits
+			bits -= 2
+		}
+		if bits >= 8 {
+			bits -= 8
+			u[pos] = byte(acc >> bits)
+			pos++
+		}
+	}
+
+	return u, nil
+}
+
+// String returns the 26 character Crockford base32 form of the ULID
+func (u ULID) String() string {
+	out := make([]byte, ulidEncodedSize)
+
+	// 130 bits of output hold the 128 bits of the ULID with two leading zero bits
+	for i := range out {
+		out[i] = crockford[ulidBits(u, i*5-2)]
+	}
+
+	return string(out)
+}
+
+// Time returns the timestamp of the ULID
+func (u ULID) Time() time.Time {
+	var b [8]byte
+	copy(b[2:], u[:6])
+
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(b[:])))
+}
+
+func (u *ULID) setTime(t time.Time) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(t.UnixMilli()))
+	copy(u[:6], b[2:])
+}
+
+// ulidBits returns the 5 bits starting at bit offset of u, offsets below zero read as zero
+func ulidBits(u ULID, offset int) byte {
+	var v byte
+	for i := 0; i < 5; i++ {
+		bit := offset + i
+		v <<= 1
+		if bit >= 0 && u[bit/8]&(0x80>>(bit%8)) != 0 {
+			v |= 1
+		}
+	}
+
+	return v
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
+
+// MonotonicULID generates ULIDs that strictly increase, even within the same millisecond,
+// by incrementing the random part of the previous ULID instead of drawing new randomness
+type MonotonicULID struct {
+	mu   sync.Mutex
+	last ULID
+}
+
+// NewMonotonicULID creates a monotonic ULID generator
+func NewMonotonicULID() *MonotonicULID {
+	return &MonotonicULID{}
+}
+
+// New returns the next ULID
+func (g *MonotonicULID) New() (ULID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if now.UnixMilli() > g.last.Time().UnixMilli() {
+		u, err := newULID(now)
+		if err != nil {
+			return ULID{}, err
+		}
+		g.last = u
+		return u, nil
+	}
+
+	next := g.last
+	if increment(next[6:]) {
+		return ULID{}, errOverflow
+	}
+	g.last = next
+
+	return next, nil
+}
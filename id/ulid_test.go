@@ -0,0 +1,95 @@
+package id
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestULIDEncoding(t *testing.T) {
+	tests := []struct {
+		s   string
+		hex string
+	}{
+		{"00000000000000000000000000", "00000000000000000000000000000000"},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01563e3ab5d3d6764c61efb99302bd5b"},
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "ffffffffffffffffffffffffffffffff"},
+	}
+	for _, tt := range tests {
+		u, err := ParseULID(tt.s)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.s, err)
+		}
+		if got := hex.EncodeToString(u[:]); got != tt.hex {
+			t.Fatalf("%s: got %s, want %s", tt.s, got, tt.hex)
+		}
+		if u.String() != tt.s {
+			t.Fatalf("%s: encoded as %s", tt.s, u)
+		}
+		if lower, err := ParseULID(strings.ToLower(tt.s)); err != nil || lower != u {
+			t.Fatalf("%s: lower case decodes to %s, %v", tt.s, lower, err)
+		}
+	}
+
+	u, _ := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if got := u.Time().UnixMilli(); got != 1469922850259 {
+		t.Fatalf("got time %d", got)
+	}
+}
+
+func TestParseULIDErrors(t *testing.T) {
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA", errLength},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAVX", errLength},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAU", errChar},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FA!", errChar},
+		{"80000000000000000000000000", errOverflow},
+	}
+	for _, tt := range tests {
+		if _, err := ParseULID(tt.s); !errors.Is(err, tt.err) {
+			t.Fatalf("%s: expected %v, got %v", tt.s, tt.err, err)
+		}
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	u, err := NewULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Time().Before(now) || u.Time().After(time.Now()) {
+		t.Fatalf("time %v is not the current time", u.Time())
+	}
+}
+
+func TestMonotonicULID(t *testing.T) {
+	g := NewMonotonicULID()
+	prev, err := g.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		next, err := g.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(next[:], prev[:]) <= 0 || next.String() <= prev.String() {
+			t.Fatalf("%s does not follow %s", next, prev)
+		}
+		prev = next
+	}
+
+	// the random part overflows within the same millisecond
+	g.last.setTime(time.Now().Add(time.Hour))
+	copy(g.last[6:], bytes.Repeat([]byte{0xff}, 10))
+	if _, err := g.New(); !errors.Is(err, errOverflow) {
+		t.Fatalf("expected errOverflow, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package id
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"strings"
+	"time"
+)
+
+// UUID is an RFC 9562 universally unique identifier
+type UUID [16]byte
+
+// NilUUID is the UUID with all bits set to zero
+var NilUUID UUID
+
+// NewUUIDv4 returns a random version 4 UUID
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(entropy, u[:]); err != nil {
+		return NilUUID, err
+	}
+	u.setVersion(4)
+
+	return u, nil
+}
+
+// NewUUIDv7 returns a time-ordered version 7 UUID made of the current Unix millisecond timestamp
+// followed by random bits
+func NewUUIDv7() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(entropy, u[6:]); err != nil {
+		return NilUUID, err
+	}
+
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(time.Now().UnixMilli()))
+	copy(u[:6], ms[2:])
+	u.setVersion(7)
+
+	return u, nil
+}
+
+// ParseUUID decodes a UUID in its canonical 8-4-4-4-12 hex form, optionally wrapped
+// in braces or prefixed with 'urn:uuid:', or as 32 hex digits without hyphens
+func ParseUUID(s string) (UUID, error) {
+	if len(s) == 45 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	} else if len(s) == 38 && s[0] == '{' && s[37] == '}' {
+		s = s[1:37]
+	}
+
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return NilUUID, errChar
+		}
+		s = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+	default:
+		return NilUUID, errLength
+	}
+
+	var u UUID
+	if _, err := hex.Decode(u[:], []byte(s)); err != nil {
+		return NilUUID, errChar
+	}
+
+	return u, nil
+}
+
+// String returns the canonical lowercase 8-4-4-4-12 hex form of the UUID
+func (u UUID) String() string {
+	var out [36]byte
+	hex.Encode(out[0:8], u[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], u[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], u[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], u[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], u[10:])
+
+	return string(out[:])
+}
+
+// Version returns the version number of the UUID
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Time returns the timestamp of a version 7 UUID, ok is false for other versions
+func (u UUID) Time() (t time.Time, ok bool) {
+	if u.Version() != 7 {
+		return time.Time{}, false
+	}
+
+	var ms [8]byte
+	copy(ms[2:], u[:6])
+
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:]))), true
+}
+
+func (u *UUID) setVersion(v byte) {
+	u[6] = u[6]&0x0f | v<<4
+	// RFC 9562 variant 10xx
+	u[8] = u[8]&0x3f | 0x80
+}
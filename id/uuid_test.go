@@ -0,0 +1,96 @@
+package id
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// withEntropy replaces the random source for the duration of a test
+func withEntropy(t *testing.T, data []byte) {
+	t.Helper()
+	prev := entropy
+	entropy = bytes.NewReader(data)
+	t.Cleanup(func() { entropy = prev })
+}
+
+func TestUUIDVectors(t *testing.T) {
+	// examples of RFC 9562 appendix A
+	tests := []struct {
+		s       string
+		version int
+		time    int64
+	}{
+		{"919108f7-52d1-4320-9bac-f847db4148a8", 4, 0},
+		{"017f22e2-79b0-7cc3-98c4-dc0c0c07398f", 7, 1645557742000},
+	}
+	for _, tt := range tests {
+		u, err := ParseUUID(tt.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.String() != tt.s || u.Version() != tt.version {
+			t.Fatalf("%s: got %s version %d", tt.s, u, u.Version())
+		}
+		ts, ok := u.Time()
+		if ok != (tt.version == 7) || (ok && ts.UnixMilli() != tt.time) {
+			t.Fatalf("%s: got time %v, %v", tt.s, ts, ok)
+		}
+	}
+}
+
+func TestParseUUIDForms(t *testing.T) {
+	want, err := ParseUUID("919108f7-52d1-4320-9bac-f847db4148a8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		s   string
+		err error
+	}{
+		{"919108F7-52D1-4320-9BAC-F847DB4148A8", nil},
+		{"{919108f7-52d1-4320-9bac-f847db4148a8}", nil},
+		{"urn:uuid:919108f7-52d1-4320-9bac-f847db4148a8", nil},
+		{"URN:UUID:919108f7-52d1-4320-9bac-f847db4148a8", nil},
+		{"919108f752d143209bacf847db4148a8", nil},
+		{"919108f7-52d1-4320-9bac-f847db4148a", errLength},
+		{"{919108f7-52d1-4320-9bac-f847db4148a8", errLength},
+		{"919108f7_52d1-4320-9bac-f847db4148a8", errChar},
+		{"919108f7-52d1-4320-9bac-f847db4148ag", errChar},
+	}
+	for _, tt := range tests {
+		u, err := ParseUUID(tt.s)
+		if !errors.Is(err, tt.err) || (err == nil && u != want) {
+			t.Fatalf("%s: got %s, %v", tt.s, u, err)
+		}
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	withEntropy(t, bytes.Repeat([]byte{0xff}, 32))
+
+	v4, err := NewUUIDv4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v4.String() != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+		t.Fatalf("version or variant bits not set: %s", v4)
+	}
+
+	before := time.Now().UnixMilli()
+	v7, err := NewUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts, ok := v7.Time()
+	if !ok || ts.UnixMilli() < before || ts.After(time.Now()) || v7[8]&0xc0 != 0x80 {
+		t.Fatalf("got %s at %v", v7, ts)
+	}
+
+	// the entropy is used up
+	if _, err := NewUUIDv4(); err == nil {
+		t.Fatal("expected an error from the exhausted entropy source")
+	}
+}
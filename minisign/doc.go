@@ -0,0 +1,7 @@
+/*
+Package minisign reads and writes the Ed25519 key and signature files of minisign and signify.
+
+Release artifacts signed with it can be checked with the minisign and signify command line tools,
+and signatures made by these tools can be verified in Go.
+*/
+package minisign
@@ -0,0 +1,254 @@
+package minisign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	commentPrefix = "untrusted comment: "
+	keyIDSize     = 8
+	saltSize      = 32
+	// opslimit and memlimit of 'minisign -G', resulting in scrypt N=2^20, r=8, p=1
+	defaultOpsLimit = 33554432
+	defaultMemLimit = 1073741824
+	// upper bounds of the scrypt parameters read from key files, N·r of the defaults uses 1 GiB
+	maxScryptNR = 1 << 23
+	maxScryptP  = 16
+)
+
+var (
+	algEd25519 = []byte("Ed")
+	kdfScrypt  = []byte("Sc")
+	kdfNone    = []byte{0, 0}
+	cksumB2    = []byte("B2")
+)
+
+// Errors of key and signature parsing
+var (
+	ErrMalformed        = errors.New("malformed minisign data")
+	ErrPassword         = errors.New("wrong password or corrupted secret key")
+	ErrKeyIDMismatch    = errors.New("signature was made by a different key")
+	ErrInvalidSignature = errors.New("signature verification failed")
+	ErrKDFLimits        = errors.New("key derivation limits of the secret key exceed the supported maximum")
+)
+
+// PublicKey is an Ed25519 public key with the 8 byte key ID of minisign and signify
+type PublicKey struct {
+	KeyID [keyIDSize]byte
+	Key   ed25519.PublicKey
+}
+
+// PrivateKey is an Ed25519 private key with the 8 byte key ID of minisign and signify
+type PrivateKey struct {
+	KeyID [keyIDSize]byte
+	Key   ed25519.PrivateKey
+}
+
+// GenerateKey creates a new key with a random key ID
+func GenerateKey() (*PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPrivateKey(key)
+}
+
+// NewPrivateKey wraps an existing Ed25519 key and assigns it a random key ID
+func NewPrivateKey(key ed25519.PrivateKey) (*PrivateKey, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, ErrMalformed
+	}
+
+	k := &PrivateKey{Key: key}
+	if _, err := io.ReadFull(rand.Reader, k.KeyID[:]); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// Public returns the public half of the key
+func (k *PrivateKey) Public() *PublicKey {
+	return &PublicKey{
+		KeyID: k.KeyID,
+		Key:   k.Key.Public().(ed25519.PublicKey),
+	}
+}
+
+// ID returns the key ID as uppercase hex, the way minisign prints it
+func (k *PublicKey) ID() string {
+	return keyIDString(k.KeyID)
+}
+
+// MarshalPublicKey encodes a public key file as written by 'minisign -G' and 'signify -G'
+func MarshalPublicKey(key *PublicKey) []byte {
+	payload := concat(algEd25519, key.KeyID[:], key.Key)
+
+	return encodeFile("minisign public key "+key.ID(), payload)
+}
+
+// ParsePublicKey decodes a minisign or signify public key file, or the bare base64 line of 'minisign -P'
+func ParsePublicKey(data []byte) (*PublicKey, error) {
+	payload, err := decodeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 2+keyIDSize+ed25519.PublicKeySize || !bytes.Equal(payload[:2], algEd25519) {
+		return nil, ErrMalformed
+	}
+
+	k := &PublicKey{Key: ed25519.PublicKey(payload[2+keyIDSize:])}
+	copy(k.KeyID[:], payload[2:])
+
+	return k, nil
+}
+
+// MarshalPrivateKey encodes a minisign secret key file, encrypted with scrypt using the parameters
+// of 'minisign -G' or unencrypted if password is empty
+func MarshalPrivateKey(key *PrivateKey, password string) ([]byte, error) {
+	keynum := concat(key.KeyID[:], key.Key)
+	sum := blake2b.Sum256(concat(algEd25519, keynum))
+	keynum = append(keynum, sum[:]...)
+
+	kdf, salt := kdfNone, make([]byte, saltSize)
+	var ops, mem uint64
+	if password != "" {
+		kdf, ops, mem = kdfScrypt, defaultOpsLimit, defaultMemLimit
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, err
+		}
+		if err := xorScrypt(keynum, password, salt, ops, mem); err != nil {
+			return nil, err
+		}
+	}
+
+	limits := make([]byte, 16)
+	binary.LittleEndian.PutUint64(limits, ops)
+	binary.LittleEndian.PutUint64(limits[8:], mem)
+	payload := concat(algEd25519, kdf, cksumB2, salt, limits, keynum)
+
+	return encodeFile("minisign encrypted secret key", payload), nil
+}
+
+// ParsePrivateKey decodes a minisign secret key file, password is ignored for unencrypted keys
+func ParsePrivateKey(data []byte, password string) (*PrivateKey, error) {
+	payload, err := decodeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	const keynumSize = keyIDSize + ed25519.PrivateKeySize + blake2b.Size256
+	if len(payload) != 6+saltSize+16+keynumSize || !bytes.Equal(payload[:2], algEd25519) || !bytes.Equal(payload[4:6], cksumB2) {
+		return nil, ErrMalformed
+	}
+
+	salt := payload[6 : 6+saltSize]
+	ops := binary.LittleEndian.Uint64(payload[6+saltSize:])
+	mem := binary.LittleEndian.Uint64(payload[6+saltSize+8:])
+	keynum := bytes.Clone(payload[6+saltSize+16:])
+
+	switch {
+	case bytes.Equal(payload[2:4], kdfScrypt):
+		if err := xorScrypt(keynum, password, salt, ops, mem); err != nil {
+			return nil, err
+		}
+	case !bytes.Equal(payload[2:4], kdfNone):
+		return nil, ErrMalformed
+	}
+
+	sum := blake2b.Sum256(concat(algEd25519, keynum[:keyIDSize+ed25519.PrivateKeySize]))
+	if subtle.ConstantTimeCompare(sum[:], keynum[keyIDSize+ed25519.PrivateKeySize:]) != 1 {
+		return nil, ErrPassword
+	}
+
+	k := &PrivateKey{Key: ed25519.PrivateKey(keynum[keyIDSize : keyIDSize+ed25519.PrivateKeySize])}
+	copy(k.KeyID[:], keynum)
+
+	return k, nil
+}
+
+// xorScrypt en- or decrypts the key material with the scrypt stream of libsodium's
+// crypto_pwhash_scryptsalsa208sha256
+func xorScrypt(keynum []byte, password string, salt []byte, ops, mem uint64) error {
+	n, r, p := scryptParams(ops, mem)
+	// the limits come from the key file, bound them before scrypt allocates 128·N·r bytes
+	if n > maxScryptNR/r || p > maxScryptP {
+		return fmt.Errorf("%w: scrypt N=%d r=%d p=%d", ErrKDFLimits, n, r, p)
+	}
+	stream, err := scrypt.Key([]byte(password), salt, n, r, p, len(keynum))
+	if err != nil {
+		return err
+	}
+	subtle.XORBytes(keynum, keynum, stream)
+
+	return nil
+}
+
+// scryptParams mirrors libsodium's pickparams, deriving scrypt N, r, and p from opslimit and memlimit
+func scryptParams(ops, mem uint64) (n, r, p int) {
+	ops = max(ops, 32768)
+	r = 8
+
+	var nLog2 uint
+	if ops < mem/32 {
+		maxN := ops / uint64(r*4)
+		for nLog2 = 1; nLog2 < 63; nLog2++ {
+			if uint64(1)<<nLog2 > maxN/2 {
+				break
+			}
+		}
+		return 1 << nLog2, r, 1
+	}
+
+	maxN := mem / uint64(r*128)
+	for nLog2 = 1; nLog2 < 63; nLog2++ {
+		if uint64(1)<<nLog2 > maxN/2 {
+			break
+		}
+	}
+	maxRP := min((ops/4)/(uint64(1)<<nLog2), 0x3fffffff)
+
+	return 1 << nLog2, r, int(maxRP / uint64(r))
+}
+
+func encodeFile(comment string, payload []byte) []byte {
+	return []byte(commentPrefix + comment + "\n" + base64.StdEncoding.EncodeToString(payload) + "\n")
+}
+
+// decodeFile returns the payload of the first base64 line, an untrusted comment line is optional
+func decodeFile(data []byte) ([]byte, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		payload, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return payload, nil
+	}
+
+	return nil, ErrMalformed
+}
+
+func keyIDString(id [keyIDSize]byte) string {
+	// minisign prints the ID as little-endian 64 bit integer
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
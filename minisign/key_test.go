@@ -0,0 +1,88 @@
+package minisign
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestScryptParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		ops, mem uint64
+		n, r, p  int
+	}{
+		{"minisign defaults", defaultOpsLimit, defaultMemLimit, 1 << 20, 8, 1},
+		{"interactive", 524288, 16777216, 1 << 14, 8, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, r, p := scryptParams(tt.ops, tt.mem)
+			if n != tt.n || r != tt.r || p != tt.p {
+				t.Fatalf("got N=%d r=%d p=%d, want N=%d r=%d p=%d", n, r, p, tt.n, tt.r, tt.p)
+			}
+		})
+	}
+}
+
+func TestParsePrivateKeyRejectsExcessiveLimits(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		ops, mem uint64
+	}{
+		{"memory", defaultOpsLimit << 10, defaultMemLimit << 10},
+		{"parallelism", 1 << 40, defaultMemLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := decodeFile(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			copy(payload[2:4], kdfScrypt)
+			binary.LittleEndian.PutUint64(payload[6+saltSize:], tt.ops)
+			binary.LittleEndian.PutUint64(payload[6+saltSize+8:], tt.mem)
+			crafted := encodeFile("crafted", payload)
+
+			if _, err := ParsePrivateKey(crafted, "password"); !errors.Is(err, ErrKDFLimits) {
+				t.Fatalf("got %v, want %v", err, ErrKDFLimits)
+			}
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := Sign(key, []byte("message"), "trusted")
+
+	comment, err := Verify(key.Public(), []byte("message"), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comment != "trusted" {
+		t.Fatalf("got comment %q", comment)
+	}
+	if _, err := Verify(key.Public(), []byte("tampered"), sig); err == nil {
+		t.Fatal("tampered message verified")
+	}
+
+	parsed, err := ParsePublicKey(MarshalPublicKey(key.Public()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ID() != key.Public().ID() || !parsed.Key.Equal(key.Public().Key) {
+		t.Fatal("public key did not round-trip")
+	}
+}
@@ -0,0 +1,82 @@
+package minisign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+const trustedPrefix = "trusted comment: "
+
+var (
+	// legacy signatures over the message itself
+	sigEd25519 = []byte("Ed")
+	// signatures over the BLAKE2b-512 digest of the message, the default since minisign 0.10
+	sigEd25519Prehashed = []byte("ED")
+)
+
+// Sign returns a minisign signature file for msg with the given trusted comment,
+// which defaults to the signing time like 'minisign -S'.
+// The message is prehashed with BLAKE2b-512, so it is accepted by 'minisign -V'.
+func Sign(key *PrivateKey, msg []byte, trustedComment string) []byte {
+	if trustedComment == "" {
+		trustedComment = fmt.Sprintf("timestamp:%d", time.Now().Unix())
+	}
+	trustedComment = strings.Join(strings.Fields(trustedComment), " ")
+
+	digest := blake2b.Sum512(msg)
+	sig := ed25519.Sign(key.Key, digest[:])
+	globalSig := ed25519.Sign(key.Key, concat(sig, []byte(trustedComment)))
+
+	var buf bytes.Buffer
+	buf.Write(encodeFile("signature from minisign secret key", concat(sigEd25519Prehashed, key.KeyID[:], sig)))
+	buf.WriteString(trustedPrefix + trustedComment + "\n")
+	buf.WriteString(base64.StdEncoding.EncodeToString(globalSig) + "\n")
+
+	return buf.Bytes()
+}
+
+// Verify checks a minisign signature file of msg, both prehashed and legacy signatures are accepted.
+// It returns the verified trusted comment.
+func Verify(key *PublicKey, msg, sigFile []byte) (string, error) {
+	lines := strings.Split(strings.TrimSpace(string(sigFile)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], trustedPrefix) {
+		return "", ErrMalformed
+	}
+
+	payload, err := decodeFile([]byte(lines[1]))
+	if err != nil {
+		return "", err
+	}
+	globalSig, err := decodeFile([]byte(lines[3]))
+	if err != nil {
+		return "", err
+	}
+	if len(payload) != 2+keyIDSize+ed25519.SignatureSize || len(globalSig) != ed25519.SignatureSize {
+		return "", ErrMalformed
+	}
+	if !bytes.Equal(payload[2:2+keyIDSize], key.KeyID[:]) {
+		return "", ErrKeyIDMismatch
+	}
+
+	sig := payload[2+keyIDSize:]
+	switch {
+	case bytes.Equal(payload[:2], sigEd25519Prehashed):
+		digest := blake2b.Sum512(msg)
+		msg = digest[:]
+	case !bytes.Equal(payload[:2], sigEd25519):
+		return "", ErrMalformed
+	}
+
+	trustedComment := strings.TrimRight(strings.TrimPrefix(lines[2], trustedPrefix), "\r")
+	if !ed25519.Verify(key.Key, msg, sig) || !ed25519.Verify(key.Key, concat(sig, []byte(trustedComment)), globalSig) {
+		return "", ErrInvalidSignature
+	}
+
+	return trustedComment, nil
+}
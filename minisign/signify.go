@@ -0,0 +1,83 @@
+package minisign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const signifySaltSize = 16
+
+var (
+	kdfBcrypt = []byte("BK")
+
+	errSignifyEncrypted = errors.New("passphrase protected signify keys are not supported, use 'signify -G -n'")
+)
+
+// MarshalSignifyPrivateKey encodes an unencrypted signify secret key file as written by 'signify -G -n'
+func MarshalSignifyPrivateKey(key *PrivateKey) []byte {
+	sum := sha512.Sum512(key.Key)
+	// kdf rounds of 0 mark the key as unencrypted
+	payload := concat(algEd25519, kdfBcrypt, make([]byte, 4), make([]byte, signifySaltSize), sum[:8], key.KeyID[:], key.Key)
+
+	return encodeFile("signify secret key", payload)
+}
+
+// ParseSignifyPrivateKey decodes an unencrypted signify secret key file
+func ParseSignifyPrivateKey(data []byte) (*PrivateKey, error) {
+	payload, err := decodeFile(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 8+signifySaltSize+8+keyIDSize+ed25519.PrivateKeySize || !bytes.Equal(payload[:2], algEd25519) || !bytes.Equal(payload[2:4], kdfBcrypt) {
+		return nil, ErrMalformed
+	}
+	if binary.BigEndian.Uint32(payload[4:8]) != 0 {
+		return nil, errSignifyEncrypted
+	}
+
+	checksum := payload[8+signifySaltSize : 16+signifySaltSize]
+	k := &PrivateKey{Key: ed25519.PrivateKey(bytes.Clone(payload[16+signifySaltSize+keyIDSize:]))}
+	copy(k.KeyID[:], payload[16+signifySaltSize:])
+
+	sum := sha512.Sum512(k.Key)
+	if subtle.ConstantTimeCompare(sum[:8], checksum) != 1 {
+		return nil, ErrMalformed
+	}
+
+	return k, nil
+}
+
+// MarshalSignifyPublicKey encodes a signify public key file
+func MarshalSignifyPublicKey(key *PublicKey) []byte {
+	return encodeFile("signify public key", concat(algEd25519, key.KeyID[:], key.Key))
+}
+
+// SignSignify returns a signify signature file for msg, as written by 'signify -S'
+func SignSignify(key *PrivateKey, msg []byte) []byte {
+	sig := ed25519.Sign(key.Key, msg)
+
+	return encodeFile("verify with signify public key", concat(algEd25519, key.KeyID[:], sig))
+}
+
+// VerifySignify checks a signify signature file of msg
+func VerifySignify(key *PublicKey, msg, sigFile []byte) error {
+	payload, err := decodeFile(sigFile)
+	if err != nil {
+		return err
+	}
+	if len(payload) != 2+keyIDSize+ed25519.SignatureSize || !bytes.Equal(payload[:2], algEd25519) {
+		return ErrMalformed
+	}
+	if !bytes.Equal(payload[2:2+keyIDSize], key.KeyID[:]) {
+		return ErrKeyIDMismatch
+	}
+	if !ed25519.Verify(key.Key, msg, payload[2+keyIDSize:]) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
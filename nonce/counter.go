@@ -0,0 +1,97 @@
+package nonce
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const reserveBatch = 1024
+
+var errExhausted = errors.New("nonce counter is exhausted")
+
+// Counter generates nonces from a monotonic 64 bit counter whose state is persisted to a file.
+//
+// Counter values are reserved in batches: the upper bound of a batch is written to disc before any
+// of its values is handed out, so a crash skips the rest of the batch instead of reusing values.
+// The state file must never be restored from a backup or shared between processes.
+type Counter struct {
+	mu       sync.Mutex
+	path     string
+	size     int
+	next     uint64
+	reserved uint64
+}
+
+// NewCounter creates a counter persisting its state at path and returning nonces of size bytes,
+// the counter is placed big endian in the last 8 bytes and the leading bytes are zero
+func NewCounter(path string, size int) (*Counter, error) {
+	if size < 8 {
+		return nil, errSize
+	}
+
+	c := &Counter{path: path, size: size}
+
+	cntnt, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		v, err := strconv.ParseUint(strings.TrimSpace(string(cntnt)), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		c.next = v
+		c.reserved = v
+	}
+
+	return c, nil
+}
+
+// Next returns the next nonce
+func (c *Counter) Next() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.next == c.reserved {
+		if c.reserved > ^uint64(0)-reserveBatch {
+			return nil, errExhausted
+		}
+		if err := c.persist(c.reserved + reserveBatch); err != nil {
+			return nil, err
+		}
+		c.reserved += reserveBatch
+	}
+
+	n := make([]byte, c.size)
+	binary.BigEndian.PutUint64(n[c.size-8:], c.next)
+	c.next++
+
+	return n, nil
+}
+
+func (c *Counter) persist(v uint64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.WriteString(strconv.FormatUint(v, 10) + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,9 @@
+/*
+Package nonce generates nonces for AEAD ciphers and similar constructions.
+
+Random nonces are the simplest choice, but their collision probability grows with the number of
+nonces used under a single key; CollisionProbability and MaxNonces quantify that bound.
+Counter nonces never collide as long as their persisted state is not rolled back,
+and timestamp nonces combine the current time with random bytes.
+*/
+package nonce
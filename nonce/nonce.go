@@ -0,0 +1,65 @@
+package nonce
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"time"
+)
+
+// Size of the nonces used by AES-GCM and ChaCha20-Poly1305, and by XChaCha20-Poly1305
+const (
+	StandardSize = 12
+	ExtendedSize = 24
+)
+
+var errSize = errors.New("nonce size is too small")
+
+// Random returns a nonce of size random bytes
+func Random(size int) ([]byte, error) {
+	if size <= 0 {
+		return nil, errSize
+	}
+
+	n := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, n); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// CollisionProbability approximates the probability that at least two of count
+// random nonces of size bytes are equal, using the birthday bound count² / 2^(8*size+1)
+func CollisionProbability(size int, count float64) float64 {
+	p := count * count / math.Exp2(float64(8*size+1))
+	if p > 1 {
+		return 1
+	}
+
+	return p
+}
+
+// MaxNonces returns how many random nonces of size bytes can be used under a single key
+// before the collision probability exceeds p, e.g. about 2^32 for 12 byte nonces and p = 2^-32
+func MaxNonces(size int, p float64) float64 {
+	return math.Sqrt(p * math.Exp2(float64(8*size+1)))
+}
+
+// Timestamp returns a nonce of size bytes made of the current Unix time in nanoseconds
+// (8 bytes, big endian) followed by random bytes. Size must be at least 12.
+func Timestamp(size int) ([]byte, error) {
+	if size < StandardSize {
+		return nil, errSize
+	}
+
+	n := make([]byte, size)
+	binary.BigEndian.PutUint64(n, uint64(time.Now().UnixNano()))
+	if _, err := io.ReadFull(rand.Reader, n[8:]); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,133 @@
+package nonce
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRandom(t *testing.T) {
+	tests := []struct {
+		size int
+		err  error
+	}{
+		{StandardSize, nil},
+		{ExtendedSize, nil},
+		{0, errSize},
+		{-1, errSize},
+	}
+	for _, tt := range tests {
+		n, err := Random(tt.size)
+		if !errors.Is(err, tt.err) || (err == nil && len(n) != tt.size) {
+			t.Fatalf("size %d: got %d bytes, %v", tt.size, len(n), err)
+		}
+	}
+
+	a, _ := Random(StandardSize)
+	b, _ := Random(StandardSize)
+	if string(a) == string(b) {
+		t.Fatal("two random nonces are equal")
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	before := time.Now().UnixNano()
+	n, err := Timestamp(StandardSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := int64(binary.BigEndian.Uint64(n))
+	if ts < before || ts > time.Now().UnixNano() {
+		t.Fatalf("timestamp %d is not the current time", ts)
+	}
+
+	if _, err := Timestamp(StandardSize - 1); !errors.Is(err, errSize) {
+		t.Fatalf("expected errSize, got %v", err)
+	}
+}
+
+func TestBirthdayBound(t *testing.T) {
+	tests := []struct {
+		size  int
+		count float64
+		p     float64
+	}{
+		{StandardSize, math.Exp2(32), math.Exp2(-33)},
+		{ExtendedSize, math.Exp2(80), math.Exp2(-33)},
+		{1, 1000, 1},
+	}
+	for _, tt := range tests {
+		if got := CollisionProbability(tt.size, tt.count); got != tt.p {
+			t.Fatalf("size %d, %g nonces: got %g, want %g", tt.size, tt.count, got, tt.p)
+		}
+	}
+
+	// about 2^32 nonces of 12 bytes for p = 2^-32
+	if got := MaxNonces(StandardSize, math.Exp2(-32)); math.Abs(got/math.Exp2(32.5)-1) > 1e-12 {
+		t.Fatalf("got %g", got)
+	}
+	if got := CollisionProbability(StandardSize, MaxNonces(StandardSize, 1e-9)); math.Abs(got/1e-9-1) > 1e-12 {
+		t.Fatalf("MaxNonces and CollisionProbability disagree: %g", got)
+	}
+}
+
+func TestCounterPersistsReservations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	c, err := NewCounter(path, StandardSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for want := uint64(0); want < 3; want++ {
+		n, err := c.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(n) != StandardSize || binary.BigEndian.Uint32(n) != 0 || binary.BigEndian.Uint64(n[4:]) != want {
+			t.Fatalf("got %x, want counter %d", n, want)
+		}
+	}
+	cntnt, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(cntnt)) != "1024" {
+		t.Fatalf("persisted %q, want the end of the first batch", cntnt)
+	}
+
+	// a restart continues after the reserved batch, never reusing values
+	c, err = NewCounter(path, StandardSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := c.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := binary.BigEndian.Uint64(n[4:]); got != reserveBatch {
+		t.Fatalf("got counter %d after restart, want %d", got, reserveBatch)
+	}
+}
+
+func TestCounterExhausted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	if err := os.WriteFile(path, []byte("18446744073709551615\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewCounter(path, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Next(); !errors.Is(err, errExhausted) {
+		t.Fatalf("expected errExhausted, got %v", err)
+	}
+
+	if _, err := NewCounter(path, 7); !errors.Is(err, errSize) {
+		t.Fatalf("expected errSize, got %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+/*
+Package qr renders RSA public keys and their fingerprints as QR codes.
+
+Codes are rendered as PNG images or as UTF-8 half block characters for terminals,
+so keys can be transferred to mobile devices or verified out-of-band.
+*/
+package qr
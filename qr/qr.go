@@ -0,0 +1,64 @@
+package qr
+
+import (
+	"crypto/rsa"
+	"errors"
+
+	"github.com/abecodes/goutls/rsakys"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Format is the output format of the rendering functions
+type Format uint
+
+// Supported output formats
+const (
+	// PNG renders a PNG image
+	PNG Format = iota
+	// Terminal renders UTF-8 half block characters for terminals with a dark background
+	Terminal
+)
+
+// pngSize renders every QR module as 8x8 pixels, go-qrcode treats negative sizes as module size
+const pngSize = -8
+
+var errFormat = errors.New("unknown QR output format")
+
+// Render renders text as QR code in the given format
+func Render(text string, format Format) ([]byte, error) {
+	code, err := qrcode.New(text, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case PNG:
+		return code.PNG(pngSize)
+	case Terminal:
+		return []byte(code.ToSmallString(false)), nil
+	default:
+		return nil, errFormat
+	}
+}
+
+// RenderPublicKey renders the PKIX PEM block of a given RSA public key as QR code,
+// so it can be transferred to mobile devices
+func RenderPublicKey(publicKey *rsa.PublicKey, format Format) ([]byte, error) {
+	block, err := rsakys.GetPKIXPublicKeyString(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(string(block), format)
+}
+
+// RenderFingerprint renders the fingerprint of a given RSA public key as QR code,
+// so it can be verified out-of-band
+func RenderFingerprint(publicKey *rsa.PublicKey, format Format) ([]byte, error) {
+	fp, err := rsakys.Fingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return Render(fp, format)
+}
@@ -0,0 +1,166 @@
+package rsakys
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	armorBegin     = "-----BEGIN RSAKYS SEALED BLOB-----"
+	armorEnd       = "-----END RSAKYS SEALED BLOB-----"
+	armorLineWidth = 64
+	armorComment   = "Comment: "
+	armorRecipient = "Recipient: "
+)
+
+// Sealed is the decoded form of an armored blob
+type Sealed struct {
+	Comment    string
+	Recipients []string
+	ciphertext []byte
+}
+
+// Seal encrypts plaintext for all recipients and returns an ASCII-armored blob suitable
+// for committing to a repository. The layout is stable: a comment header, the recipient
+// fingerprints in sorted order, and the ciphertext wrapped at 64 columns.
+func Seal(plaintext []byte, recipients []*rsa.PublicKey, comment string) ([]byte, error) {
+	recipients, fps, err := sortRecipients(recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	var ciphertext bytes.Buffer
+	if err := EncryptStream(&ciphertext, bytes.NewReader(plaintext), recipients...); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(armorBegin + "\n")
+	if comment = strings.Join(strings.Fields(comment), " "); comment != "" {
+		buf.WriteString(armorComment + comment + "\n")
+	}
+	for _, fp := range fps {
+		buf.WriteString(armorRecipient + fp + "\n")
+	}
+	buf.WriteString("\n")
+
+	b64 := base64.StdEncoding.EncodeToString(ciphertext.Bytes())
+	for len(b64) > armorLineWidth {
+		buf.WriteString(b64[:armorLineWidth] + "\n")
+		b64 = b64[armorLineWidth:]
+	}
+	buf.WriteString(b64 + "\n" + armorEnd + "\n")
+
+	return buf.Bytes(), nil
+}
+
+// Open decrypts an armored blob created by Seal
+func Open(armored []byte, privateKey *rsa.PrivateKey) ([]byte, error) {
+	s, err := ParseSealed(armored)
+	if err != nil {
+		return nil, err
+	}
+
+	fp, err := Fingerprint(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(s.Recipients, fp) {
+		return nil, ErrWrongKey
+	}
+
+	var buf bytes.Buffer
+	if err := DecryptStream(&buf, bytes.NewReader(s.ciphertext), privateKey); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Reseal returns previous unchanged if it already holds plaintext for exactly these recipients
+// and comment, and a freshly sealed blob otherwise, so unchanged secrets do not produce diffs
+func Reseal(previous, plaintext []byte, recipients []*rsa.PublicKey, comment string, privateKey *rsa.PrivateKey) ([]byte, error) {
+	if unchanged(previous, plaintext, recipients, comment, privateKey) {
+		return previous, nil
+	}
+
+	return Seal(plaintext, recipients, comment)
+}
+
+// ParseSealed decodes the armor of a blob without decrypting it, e.g. to list its recipients
+func ParseSealed(armored []byte) (*Sealed, error) {
+	sc := bufio.NewScanner(bytes.NewReader(armored))
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != armorBegin {
+		return nil, ErrMalformed
+	}
+
+	s := &Sealed{}
+	for sc.Scan() && sc.Text() != "" {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, armorComment):
+			s.Comment = strings.TrimPrefix(line, armorComment)
+		case strings.HasPrefix(line, armorRecipient):
+			s.Recipients = append(s.Recipients, strings.TrimPrefix(line, armorRecipient))
+		default:
+			return nil, ErrMalformed
+		}
+	}
+
+	var b64 strings.Builder
+	for sc.Scan() && strings.TrimSpace(sc.Text()) != armorEnd {
+		b64.WriteString(strings.TrimSpace(sc.Text()))
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(b64.String())
+	if err != nil || len(ciphertext) == 0 {
+		return nil, ErrMalformed
+	}
+	s.ciphertext = ciphertext
+
+	return s, nil
+}
+
+func unchanged(previous, plaintext []byte, recipients []*rsa.PublicKey, comment string, privateKey *rsa.PrivateKey) bool {
+	s, err := ParseSealed(previous)
+	if err != nil || s.Comment != strings.Join(strings.Fields(comment), " ") {
+		return false
+	}
+	if _, fps, err := sortRecipients(recipients); err != nil || !slices.Equal(fps, s.Recipients) {
+		return false
+	}
+	old, err := Open(previous, privateKey)
+
+	return err == nil && bytes.Equal(old, plaintext)
+}
+
+// sortRecipients orders recipients by fingerprint and drops duplicates
+func sortRecipients(recipients []*rsa.PublicKey) ([]*rsa.PublicKey, []string, error) {
+	byFP := make(map[string]*rsa.PublicKey, len(recipients))
+	for _, r := range recipients {
+		fp, err := Fingerprint(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("recipient: %w", err)
+		}
+		byFP[fp] = r
+	}
+
+	fps := make([]string, 0, len(byFP))
+	for fp := range byFP {
+		fps = append(fps, fp)
+	}
+	sort.Strings(fps)
+
+	sorted := make([]*rsa.PublicKey, len(fps))
+	for i, fp := range fps {
+		sorted[i] = byFP[fp]
+	}
+
+	return sorted, fps, nil
+}
@@ -0,0 +1,156 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSealRoundTrip(t *testing.T) {
+	alice, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eve, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := bytes.Repeat([]byte("secret "), 100)
+	armored, err := Seal(plaintext, []*rsa.PublicKey{&bob.PublicKey, &alice.PublicKey, &bob.PublicKey}, " db \n password ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(armored), "\n"), "\n")
+	if lines[0] != armorBegin || lines[len(lines)-1] != armorEnd || lines[1] != armorComment+"db password" {
+		t.Fatalf("unexpected layout:\n%s", armored)
+	}
+	for _, line := range lines[5 : len(lines)-1] {
+		if len(line) > armorLineWidth {
+			t.Fatalf("line exceeds %d columns: %s", armorLineWidth, line)
+		}
+	}
+
+	s, err := ParseSealed(armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Recipients) != 2 || !slices.IsSorted(s.Recipients) {
+		t.Fatalf("recipients not sorted and deduplicated: %v", s.Recipients)
+	}
+
+	for _, key := range []*rsa.PrivateKey{alice, bob} {
+		got, err := Open(armored, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatal("plaintext differs")
+		}
+	}
+	if _, err := Open(armored, eve); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("expected ErrWrongKey, got %v", err)
+	}
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored, err := Seal([]byte("secret"), []*rsa.PublicKey{&key.PublicKey}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherFP, err := Fingerprint(&other.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(string(armored), "\n")
+	body := len(lines) - 4
+	flipped := slices.Clone(lines)
+	c := byte('A')
+	if flipped[body][10] == c {
+		c = 'B'
+	}
+	flipped[body] = flipped[body][:10] + string(c) + flipped[body][11:]
+
+	tests := []struct {
+		name    string
+		armored string
+		err     error
+	}{
+		{"no armor", "secret", ErrMalformed},
+		{"unknown header", strings.Replace(string(armored), "\n\n", "\nVersion: 2\n\n", 1), ErrMalformed},
+		{"bad base64", strings.Replace(string(armored), lines[body], "!!!!", 1), ErrMalformed},
+		{"empty body", armorBegin + "\n\n" + armorEnd + "\n", ErrMalformed},
+		{"foreign recipient", strings.Replace(string(armored), lines[1], armorRecipient+otherFP, 1), ErrWrongKey},
+		{"modified ciphertext", strings.Join(flipped, "\n"), nil},
+		{"truncated ciphertext", strings.Join(slices.Delete(slices.Clone(lines), body, body+1), "\n"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open([]byte(tt.armored), key)
+			if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestReseal(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipients := []*rsa.PublicKey{&key.PublicKey}
+	previous, err := Seal([]byte("secret"), recipients, "db")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		plaintext  string
+		recipients []*rsa.PublicKey
+		comment    string
+		same       bool
+	}{
+		{"unchanged", "secret", recipients, " db ", true},
+		{"plaintext", "other", recipients, "db", false},
+		{"comment", "secret", recipients, "cache", false},
+		{"recipients", "secret", []*rsa.PublicKey{&key.PublicKey, &other.PublicKey}, "db", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Reseal(previous, []byte(tt.plaintext), tt.recipients, tt.comment, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.same != bytes.Equal(got, previous) {
+				t.Fatalf("resealed: %v", !tt.same)
+			}
+			opened, err := Open(got, key)
+			if err != nil || string(opened) != tt.plaintext {
+				t.Fatalf("got %q, %v", opened, err)
+			}
+		})
+	}
+}
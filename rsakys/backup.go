@@ -0,0 +1,73 @@
+package rsakys
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const backupTimeFormat = "20060102T150405.000000000Z"
+
+// backupFile copies the key at path, already resolved by resolveKeyPath, so the original
+// stays in place until it is atomically replaced
+func backupFile(path string, o *writeOptions) error {
+	if !o.backup {
+		return nil
+	}
+
+	info, err := os.Stat(fixPath(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	cntnt, err := os.ReadFile(fixPath(path))
+	if err != nil {
+		return err
+	}
+
+	target := fmt.Sprintf("%s.bak-%s", path, time.Now().UTC().Format(backupTimeFormat))
+	if o.backupDir != "" {
+		if err := os.MkdirAll(o.backupDir, 0o700); err != nil {
+			return err
+		}
+		target = filepath.Join(o.backupDir, filepath.Base(target))
+	}
+
+	return writeAtomic(target, info.Mode().Perm(), func(w io.Writer) error {
+		_, err := w.Write(cntnt)
+		return err
+	})
+}
+
+// resolveKeyPath follows a symlink at path, so keys behind the links of RotateKeypair are
+// replaced in place instead of the link itself
+func resolveKeyPath(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return path, nil
+	}
+
+	return resolved, err
+}
+
+// writeKeyFile backs up an existing key if requested and atomically replaces it with data,
+// a failing write leaves the previous key untouched
+func writeKeyFile(path string, perm os.FileMode, data []byte, o *writeOptions) error {
+	path, err := resolveKeyPath(path)
+	if err != nil {
+		return err
+	}
+	if err := backupFile(path, o); err != nil {
+		return err
+	}
+
+	return writeAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
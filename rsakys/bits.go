@@ -0,0 +1,53 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// Common RSA key sizes in bits
+const (
+	Bits2048 = 2048
+	Bits3072 = 3072
+	Bits4096 = 4096
+)
+
+// DefaultBits is the key size used when a generation function is called with a bit size of 0
+const DefaultBits = Bits3072
+
+// ErrKeyTooSmall is returned when a key below the configured floor should be generated
+var ErrKeyTooSmall = errors.New("key size is below the configured minimum")
+
+// SetMinBits sets the floor below which no keys are generated, defaults to 2048.
+// Lowering it, e.g. for fast test keys, should be confined to tests.
+func SetMinBits(bits int) {
+	cfg := Defaults()
+	cfg.MinBits = bits
+	SetDefaults(cfg)
+}
+
+// MinBits returns the floor below which no keys are generated
+func MinBits() int {
+	return currentConfig().MinBits
+}
+
+// ValidateBits checks a bit size against the configured floor
+func ValidateBits(bitSize int) error {
+	if bitSize < MinBits() {
+		return fmt.Errorf("%w: %d < %d bits", ErrKeyTooSmall, bitSize, MinBits())
+	}
+
+	return nil
+}
+
+func generateKey(bitSize int) (*rsa.PrivateKey, error) {
+	if bitSize == 0 {
+		bitSize = DefaultBits
+	}
+	if err := ValidateBits(bitSize); err != nil {
+		return nil, err
+	}
+
+	return rsa.GenerateKey(currentConfig().Rand, bitSize)
+}
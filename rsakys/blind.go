@@ -0,0 +1,282 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"filippo.io/bigmod"
+)
+
+// BlindVariant selects one of the RSABSSA variants of RFC 9474
+type BlindVariant uint
+
+// RSABSSA variants of RFC 9474, all use SHA-384 with MGF1
+const (
+	// BlindSHA384PSSRandomized uses a 48 byte PSS salt and a random message prefix
+	BlindSHA384PSSRandomized BlindVariant = iota
+	// BlindSHA384PSSZeroRandomized uses no PSS salt and a random message prefix
+	BlindSHA384PSSZeroRandomized
+	// BlindSHA384PSSDeterministic uses a 48 byte PSS salt and no message prefix
+	BlindSHA384PSSDeterministic
+	// BlindSHA384PSSZeroDeterministic uses no PSS salt and no message prefix, signatures are deterministic
+	BlindSHA384PSSZeroDeterministic
+)
+
+const blindPrefixSize = 32
+
+var (
+	errBlindVariant = errors.New("unknown blind signature variant")
+	errBlindInput   = errors.New("blind signature input is invalid")
+)
+
+var errPrivateOp = errors.New("rsa private key operation failed")
+
+func (v BlindVariant) saltLength() (int, error) {
+	switch v {
+	case BlindSHA384PSSRandomized, BlindSHA384PSSDeterministic:
+		return sha512.Size384, nil
+	case BlindSHA384PSSZeroRandomized, BlindSHA384PSSZeroDeterministic:
+		return 0, nil
+	default:
+		return 0, errBlindVariant
+	}
+}
+
+func (v BlindVariant) randomized() bool {
+	return v == BlindSHA384PSSRandomized || v == BlindSHA384PSSZeroRandomized
+}
+
+// BlindPrepare returns the input message to blind, sign, and verify.
+// Randomized variants prefix msg with 32 random bytes, deterministic variants return msg as is.
+func BlindPrepare(variant BlindVariant, msg []byte) ([]byte, error) {
+	if _, err := variant.saltLength(); err != nil {
+		return nil, err
+	}
+	if !variant.randomized() {
+		return msg, nil
+	}
+
+	input := make([]byte, blindPrefixSize, blindPrefixSize+len(msg))
+	if _, err := io.ReadFull(currentConfig().Rand, input); err != nil {
+		return nil, err
+	}
+
+	return append(input, msg...), nil
+}
+
+// Blind blinds the prepared input message for the signer's RSA public key and returns
+// the blinded message to send to the signer and the inverse to keep for BlindFinalize
+func Blind(publicKey *rsa.PublicKey, variant BlindVariant, input []byte) (blindedMsg, inv []byte, err error) {
+	saltLen, err := variant.saltLength()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	encoded, err := emsaPSSEncode(sha512.New384(), input, publicKey.N.BitLen()-1, saltLen)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := new(big.Int).SetBytes(encoded)
+	one := big.NewInt(1)
+	if new(big.Int).GCD(nil, nil, m, publicKey.N).Cmp(one) != 0 {
+		return nil, nil, errBlindInput
+	}
+
+	var r, rInv *big.Int
+	for rInv == nil {
+		if r, err = rand.Int(currentConfig().Rand, publicKey.N); err != nil {
+			return nil, nil, err
+		}
+		if r.Sign() > 0 {
+			rInv = new(big.Int).ModInverse(r, publicKey.N)
+		}
+	}
+
+	x := new(big.Int).Exp(r, big.NewInt(int64(publicKey.E)), publicKey.N)
+	z := x.Mul(m, x).Mod(x, publicKey.N)
+
+	size := publicKey.Size()
+
+	return z.FillBytes(make([]byte, size)), rInv.FillBytes(make([]byte, size)), nil
+}
+
+// BlindSign signs a blinded message with the RSA private key, the signer learns nothing about the input
+func BlindSign(privateKey *rsa.PrivateKey, blindedMsg []byte) ([]byte, error) {
+	n := privateKey.N
+	if err := checkSigningKey(n); err != nil {
+		return nil, err
+	}
+	if len(blindedMsg) != privateKey.Size() {
+		return nil, errBlindInput
+	}
+	m := new(big.Int).SetBytes(blindedMsg)
+	if m.Cmp(n) >= 0 {
+		return nil, errBlindInput
+	}
+
+	s, err := rsaSignRaw(privateKey, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.FillBytes(make([]byte, privateKey.Size())), nil
+}
+
+// BlindFinalize unblinds the blind signature with the inverse returned by Blind
+// and verifies the resulting signature over the prepared input message
+func BlindFinalize(publicKey *rsa.PublicKey, variant BlindVariant, input, blindSig, inv []byte) ([]byte, error) {
+	size := publicKey.Size()
+	if len(blindSig) != size || len(inv) != size {
+		return nil, errBlindInput
+	}
+
+	z := new(big.Int).SetBytes(blindSig)
+	s := z.Mul(z, new(big.Int).SetBytes(inv)).Mod(z, publicKey.N)
+	sig := s.FillBytes(make([]byte, size))
+
+	if err := BlindVerify(publicKey, variant, input, sig); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// BlindVerify verifies a finalized signature over the prepared input message,
+// it is a regular RSASSA-PSS verification with SHA-384
+func BlindVerify(publicKey *rsa.PublicKey, variant BlindVariant, input, sig []byte) error {
+	saltLen, err := variant.saltLength()
+	if err != nil {
+		return err
+	}
+	if saltLen == 0 {
+		// crypto/rsa treats 0 as auto detection, a salt-less signature still verifies
+		saltLen = rsa.PSSSaltLengthAuto
+	}
+
+	digest := sha512.Sum384(input)
+
+	return rsa.VerifyPSS(publicKey, crypto.SHA384, digest[:], sig, &rsa.PSSOptions{
+		SaltLength: saltLen,
+		Hash:       crypto.SHA384,
+	})
+}
+
+// rsaSignRaw computes m^d mod n for m < n with the constant time arithmetic of crypto/rsa,
+// using the CRT values of two prime keys. The result is checked against the public key,
+// so a faulty computation never leaks the key.
+func rsaSignRaw(key *rsa.PrivateKey, m *big.Int) (*big.Int, error) {
+	N, err := bigmod.NewModulusFromBig(key.N)
+	if err != nil {
+		return nil, err
+	}
+	c, err := bigmod.NewNat().SetBytes(m.Bytes(), N)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *bigmod.Nat
+	if len(key.Primes) == 2 && key.Precomputed.Dp != nil {
+		P, err := bigmod.NewModulusFromBig(key.Primes[0])
+		if err != nil {
+			return nil, err
+		}
+		Q, err := bigmod.NewModulusFromBig(key.Primes[1])
+		if err != nil {
+			return nil, err
+		}
+		qInv, err := bigmod.NewNat().SetBytes(key.Precomputed.Qinv.Bytes(), P)
+		if err != nil {
+			return nil, err
+		}
+
+		t := bigmod.NewNat()
+		// s = c^dp mod p, s2 = c^dq mod q
+		s = bigmod.NewNat().Exp(t.Mod(c, P), key.Precomputed.Dp.Bytes(), P)
+		s2 := bigmod.NewNat().Exp(t.Mod(c, Q), key.Precomputed.Dq.Bytes(), Q)
+		// s = ((s - s2) * qinv mod p) * q + s2 mod n
+		s.Sub(t.Mod(s2, P), P)
+		s.Mul(qInv, P)
+		s.ExpandFor(N).Mul(t.Mod(Q.Nat(), N), N)
+		s.Add(s2.ExpandFor(N), N)
+	} else {
+		s = bigmod.NewNat().Exp(c, key.D.Bytes(), N)
+	}
+
+	sig := new(big.Int).SetBytes(s.Bytes(N))
+	if new(big.Int).Exp(sig, big.NewInt(int64(key.E)), key.N).Cmp(m) != 0 {
+		return nil, errPrivateOp
+	}
+
+	return sig, nil
+}
+
+// emsaPSSEncode implements EMSA-PSS-ENCODE of RFC 8017 with MGF1 over the same hash
+func emsaPSSEncode(h hash.Hash, msg []byte, emBits, saltLen int) ([]byte, error) {
+	h.Reset()
+	h.Write(msg)
+	mHash := h.Sum(nil)
+
+	hLen := len(mHash)
+	emLen := (emBits + 7) / 8
+	if emLen < hLen+saltLen+2 {
+		return nil, errBlindInput
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := io.ReadFull(currentConfig().Rand, salt); err != nil {
+		return nil, err
+	}
+
+	h.Reset()
+	h.Write(make([]byte, 8))
+	h.Write(mHash)
+	h.Write(salt)
+	hh := h.Sum(nil)
+
+	em := make([]byte, emLen)
+	db := em[:emLen-hLen-1]
+	db[len(db)-saltLen-1] = 0x01
+	copy(db[len(db)-saltLen:], salt)
+	mgf1XOR(db, h, hh)
+	db[0] &= 0xff >> (8*emLen - emBits)
+	copy(em[emLen-hLen-1:], hh)
+	em[emLen-1] = 0xbc
+
+	return em, nil
+}
+
+// mgf1XOR xors out with the MGF1 mask generated from seed
+func mgf1XOR(out []byte, h hash.Hash, seed []byte) {
+	var counter [4]byte
+	var digest []byte
+
+	done := 0
+	for done < len(out) {
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter[:])
+		digest = h.Sum(digest[:0])
+
+		for i := 0; i < len(digest) && done < len(out); i++ {
+			out[done] ^= digest[i]
+			done++
+		}
+		incCounter(&counter)
+	}
+}
+
+func incCounter(c *[4]byte) {
+	for i := 3; i >= 0; i-- {
+		c[i]++
+		if c[i] != 0 {
+			return
+		}
+	}
+}
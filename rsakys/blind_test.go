@@ -0,0 +1,137 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha512"
+	"errors"
+	"testing"
+)
+
+func TestBlindSignatureRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("ticket 42")
+
+	tests := []struct {
+		name       string
+		variant    BlindVariant
+		saltLen    int
+		randomized bool
+	}{
+		{"PSS randomized", BlindSHA384PSSRandomized, sha512.Size384, true},
+		{"PSSZERO randomized", BlindSHA384PSSZeroRandomized, 0, true},
+		{"PSS deterministic", BlindSHA384PSSDeterministic, sha512.Size384, false},
+		{"PSSZERO deterministic", BlindSHA384PSSZeroDeterministic, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := BlindPrepare(tt.variant, msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.randomized != (len(input) == blindPrefixSize+len(msg)) || !bytes.HasSuffix(input, msg) {
+				t.Fatalf("unexpected prepared input of %d bytes", len(input))
+			}
+
+			blinded, inv, err := Blind(&key.PublicKey, tt.variant, input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			blindSig, err := BlindSign(key, blinded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := BlindFinalize(&key.PublicKey, tt.variant, input, blindSig, inv)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// a finalized signature is a regular RSASSA-PSS signature with the variant's salt length
+			digest := sha512.Sum384(input)
+			opts := &rsa.PSSOptions{SaltLength: tt.saltLen, Hash: crypto.SHA384}
+			if tt.saltLen == 0 {
+				opts.SaltLength = rsa.PSSSaltLengthAuto
+			}
+			if err := rsa.VerifyPSS(&key.PublicKey, crypto.SHA384, digest[:], sig, opts); err != nil {
+				t.Fatal(err)
+			}
+
+			// the signer never sees the message, the blinded message differs from it
+			if bytes.Contains(blinded, input) {
+				t.Fatal("blinded message contains the input")
+			}
+			if err := BlindVerify(&key.PublicKey, tt.variant, append(bytes.Clone(input), '!'), sig); err == nil {
+				t.Fatal("signature verified for another input")
+			}
+		})
+	}
+}
+
+func TestBlindSignatureDeterministic(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func() []byte {
+		blinded, inv, err := Blind(&key.PublicKey, BlindSHA384PSSZeroDeterministic, []byte("msg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		blindSig, err := BlindSign(key, blinded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := BlindFinalize(&key.PublicKey, BlindSHA384PSSZeroDeterministic, []byte("msg"), blindSig, inv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+
+	// different blinding factors finalize to the same signature
+	if !bytes.Equal(sign(), sign()) {
+		t.Fatal("RSABSSA-SHA384-PSSZERO-Deterministic signatures differ")
+	}
+}
+
+func TestBlindSignatureErrors(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blinded, inv, err := Blind(&key.PublicKey, BlindSHA384PSSRandomized, []byte("msg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BlindPrepare(BlindVariant(9), nil); !errors.Is(err, errBlindVariant) {
+		t.Fatalf("expected errBlindVariant, got %v", err)
+	}
+	if _, err := BlindSign(key, blinded[1:]); !errors.Is(err, errBlindInput) {
+		t.Fatalf("expected errBlindInput for a short message, got %v", err)
+	}
+	if _, err := BlindSign(key, bytes.Repeat([]byte{0xff}, key.Size())); !errors.Is(err, errBlindInput) {
+		t.Fatalf("expected errBlindInput for a message above the modulus, got %v", err)
+	}
+
+	// a signature of the wrong key does not finalize, the blinded message may also exceed its modulus
+	blindSig, err := BlindSign(other, blinded)
+	if err != nil && !errors.Is(err, errBlindInput) {
+		t.Fatal(err)
+	}
+	if err == nil {
+		if _, err := BlindFinalize(&key.PublicKey, BlindSHA384PSSRandomized, []byte("msg"), blindSig, inv); err == nil {
+			t.Fatal("finalized the signature of another key")
+		}
+	}
+}
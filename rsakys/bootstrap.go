@@ -0,0 +1,161 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"time"
+)
+
+const (
+	lockSuffix       = "lock"
+	lockPollInterval = 100 * time.Millisecond
+	lockStaleAfter   = 5 * time.Minute
+)
+
+var errLockLost = errors.New("lock was taken over by another caller")
+
+// GenerateKeypairIfNotExists loads the keypair '<keyname>.pem' in path if it exists,
+// otherwise generates it in the configured private format. It is safe to call concurrently from
+// several processes sharing the directory: an O_EXCL lock file ensures the keypair is generated
+// exactly once while the other callers wait and load it afterwards.
+// A lock left behind by a crashed process is broken after 5 minutes, each lock carries a random
+// token so a fresh lock taken in the meantime is never removed instead. A holder whose lock was
+// broken while it was generating does not write its keypair and loads the one on disk instead.
+func GenerateKeypairIfNotExists(path, keyname string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, error) {
+	privatePath := keyFilePath(path, keyname, privateSuffix)
+	lockPath := keyFilePath(path, keyname, lockSuffix)
+
+	for {
+		if _, err := os.Stat(privatePath); err == nil {
+			return readPrivate(privatePath)
+		}
+
+		token, err := acquireLock(lockPath)
+		if err == nil {
+			privateKey, err := generateLocked(path, keyname, privatePath, lockPath, token, bitSize, opts)
+			if errors.Is(err, errLockLost) {
+				continue
+			}
+			return privateKey, err
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		// read the token before the mod time, a lock replaced in between is seen as fresh
+		held, err := os.ReadFile(lockPath)
+		if err == nil {
+			if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > lockStaleAfter {
+				// a lost race only means someone else holds the lock now, keep waiting for it
+				if err := releaseLock(lockPath, held); err != nil && !errors.Is(err, errLockLost) {
+					return nil, err
+				}
+				continue
+			}
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// acquireLock exclusively creates the lock file and writes a random token into it
+func acquireLock(lockPath string) ([]byte, error) {
+	suffix, err := randomSuffix()
+	if err != nil {
+		return nil, err
+	}
+	token := []byte(suffix)
+
+	lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := lock.Write(token); err != nil {
+		lock.Close()
+		os.Remove(lockPath)
+		return nil, err
+	}
+
+	return token, lock.Close()
+}
+
+// holdsLock reports whether the lock file still carries token
+func holdsLock(lockPath string, token []byte) bool {
+	held, err := os.ReadFile(lockPath)
+
+	return err == nil && bytes.Equal(held, token)
+}
+
+// releaseLock removes the lock only if it still carries token. The lock is first renamed to a
+// unique name, so no other caller can take it over between checking and removing, and linked
+// back if it turns out to be someone else's. errLockLost is returned if the lock was not token's,
+// or if it could not be restored because another caller took the lock meanwhile.
+func releaseLock(lockPath string, token []byte) error {
+	suffix, err := randomSuffix()
+	if err != nil {
+		return err
+	}
+	moved := lockPath + ".stale-" + suffix
+	if err := os.Rename(lockPath, moved); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errLockLost
+		}
+		return err
+	}
+	defer os.Remove(moved)
+
+	held, err := os.ReadFile(moved)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(held, token) {
+		return nil
+	}
+
+	// a fresh lock, restore it unless yet another caller took the lock meanwhile
+	if err := os.Link(moved, lockPath); err != nil && !errors.Is(err, os.ErrExist) {
+		return err
+	}
+
+	return errLockLost
+}
+
+// generateLocked writes a new keypair while holding the lock. It returns errLockLost without
+// writing if the lock was broken meanwhile, and the keypair on disk if the lock was lost after writing.
+func generateLocked(path, keyname, privatePath, lockPath string, token []byte, bitSize int, opts []WriteOption) (*rsa.PrivateKey, error) {
+	// another caller may have finished between our check and taking the lock
+	if _, err := os.Stat(privatePath); err == nil {
+		_ = releaseLock(lockPath, token)
+		return readPrivate(privatePath)
+	}
+
+	privateKey, err := GetPrivateKey(bitSize)
+	if err != nil {
+		_ = releaseLock(lockPath, token)
+		return nil, err
+	}
+	if !holdsLock(lockPath, token) {
+		return nil, errLockLost
+	}
+
+	// the public key goes first, the private key's appearance signals a complete keypair
+	err = writePublicKey(keyFilePath(path, keyname, publicSuffix), &privateKey.PublicKey, PKIX, opts...)
+	if err == nil {
+		err = writePrivateKey(privatePath, privateKey, currentConfig().PrivateFormat, opts...)
+	}
+	if err != nil {
+		_ = releaseLock(lockPath, token)
+		return nil, err
+	}
+
+	if err := releaseLock(lockPath, token); err != nil {
+		if errors.Is(err, errLockLost) {
+			// another caller may have written its keypair as well, the one on disk wins
+			return readPrivate(privatePath)
+		}
+		return nil, err
+	}
+
+	return privateKey, nil
+}
@@ -0,0 +1,95 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type keyResult struct {
+	key *rsa.PrivateKey
+	err error
+}
+
+func TestGenerateKeypairIfNotExistsConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	// a lock left behind by a crashed process
+	lockPath := keyFilePath(dir, "k", lockSuffix)
+	if err := os.WriteFile(lockPath, []byte("crashed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * lockStaleAfter)
+	if err := os.Chtimes(lockPath, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	const callers = 8
+	keys := make([]*keyResult, callers)
+	var wg sync.WaitGroup
+	for i := range keys {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key, err := GenerateKeypairIfNotExists(dir, "k", Bits2048)
+			keys[i] = &keyResult{key, err}
+		}(i)
+	}
+	wg.Wait()
+
+	onDisk, err := ReadPrivate(keyFilePath(dir, "k", privateSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range keys {
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if !Equal(r.key, onDisk) {
+			t.Fatal("a caller returned a key that is not the one on disk")
+		}
+	}
+	if _, err := os.Stat(lockPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("lock was left behind: %v", err)
+	}
+}
+
+func TestReleaseLockKeepsForeignLock(t *testing.T) {
+	lockPath := keyFilePath(t.TempDir(), "k", lockSuffix)
+	token, err := acquireLock(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := releaseLock(lockPath, []byte("other")); !errors.Is(err, errLockLost) {
+		t.Fatalf("got %v, want %v", err, errLockLost)
+	}
+	if !holdsLock(lockPath, token) {
+		t.Fatal("the foreign lock was not restored")
+	}
+	if err := releaseLock(lockPath, token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(lockPath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("lock was not removed: %v", err)
+	}
+}
+
+func TestGenerateLockedAfterLockLost(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := keyFilePath(dir, "k", lockSuffix)
+	privatePath := keyFilePath(dir, "k", privateSuffix)
+	if err := os.WriteFile(lockPath, []byte("taken over"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := generateLocked(dir, "k", privatePath, lockPath, []byte("mine"), Bits2048, nil)
+	if !errors.Is(err, errLockLost) {
+		t.Fatalf("got %v, want %v", err, errLockLost)
+	}
+	if _, err := os.Stat(privatePath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatal("a holder without the lock wrote its key")
+	}
+}
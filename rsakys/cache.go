@@ -0,0 +1,173 @@
+package rsakys
+
+import (
+	"container/list"
+	"crypto/rsa"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errFetchPanicked = errors.New("key fetcher panicked")
+
+// KeyFetcher retrieves the public key identified by kid from a remote source,
+// e.g. a JWKS endpoint or a certificate URL
+type KeyFetcher func(kid string) (*rsa.PublicKey, error)
+
+// KeyCache is a bounded, TTL-aware LRU cache for remote verification keys.
+// Concurrent lookups of the same missing kid share a single fetch.
+type KeyCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	fetch   KeyFetcher
+	order   *list.List
+	entries map[string]*list.Element
+	calls   map[string]*fetchCall
+}
+
+type cacheEntry struct {
+	kid     string
+	key     *rsa.PublicKey
+	expires time.Time
+}
+
+type fetchCall struct {
+	done chan struct{}
+	key  *rsa.PublicKey
+	err  error
+	// invalidated drops the result instead of caching it
+	invalidated bool
+}
+
+// NewKeyCache creates a cache holding at most size keys for the duration of ttl.
+// A size <= 0 disables the bound, a ttl <= 0 keeps keys until they are evicted.
+func NewKeyCache(size int, ttl time.Duration, fetch KeyFetcher) *KeyCache {
+	return &KeyCache{
+		size:    size,
+		ttl:     ttl,
+		fetch:   fetch,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		calls:   make(map[string]*fetchCall),
+	}
+}
+
+// Get returns the cached key for kid, fetching it if it is missing or expired
+func (c *KeyCache) Get(kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	if key, ok := c.lookup(kid); ok {
+		c.mu.Unlock()
+		return key, nil
+	}
+
+	if call, ok := c.calls[kid]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.key, call.err
+	}
+
+	call := &fetchCall{done: make(chan struct{})}
+	c.calls[kid] = call
+	c.mu.Unlock()
+
+	// waiters are released even if fetch panics, the panic itself propagates to this caller
+	panicked := true
+	defer func() {
+		if panicked {
+			call.err = errFetchPanicked
+		}
+		c.mu.Lock()
+		delete(c.calls, kid)
+		if call.err == nil && !call.invalidated {
+			c.store(kid, call.key)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.key, call.err = c.fetch(kid)
+	panicked = false
+
+	return call.key, call.err
+}
+
+// Invalidate removes the key for kid from the cache, a fetch in flight is not cached either
+func (c *KeyCache) Invalidate(kid string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[kid]; ok {
+		c.remove(el)
+	}
+	if call, ok := c.calls[kid]; ok {
+		call.invalidated = true
+	}
+}
+
+// Purge removes all keys from the cache, fetches in flight are not cached either
+func (c *KeyCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, call := range c.calls {
+		call.invalidated = true
+	}
+
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Len returns the number of cached keys, including expired ones not yet evicted
+func (c *KeyCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *KeyCache) lookup(kid string) (*rsa.PublicKey, bool) {
+	el, ok := c.entries[kid]
+	if !ok {
+		return nil, false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.remove(el)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+
+	return entry.key, true
+}
+
+func (c *KeyCache) store(kid string, key *rsa.PublicKey) {
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = time.Now().Add(c.ttl)
+	}
+
+	if el, ok := c.entries[kid]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.key = key
+		entry.expires = expires
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[kid] = c.order.PushFront(&cacheEntry{
+		kid:     kid,
+		key:     key,
+		expires: expires,
+	})
+
+	if c.size > 0 && c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *KeyCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).kid)
+}
@@ -0,0 +1,172 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingFetcher returns a distinct dummy key per call and counts the calls per kid
+type countingFetcher struct {
+	mu    sync.Mutex
+	calls map[string]int
+	n     int64
+}
+
+func (f *countingFetcher) fetch(kid string) (*rsa.PublicKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[kid]++
+	f.n++
+
+	return &rsa.PublicKey{N: big.NewInt(f.n), E: 65537}, nil
+}
+
+func (f *countingFetcher) count(kid string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[kid]
+}
+
+func TestKeyCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	f := &countingFetcher{}
+	c := NewKeyCache(2, 0, f.fetch)
+
+	for _, kid := range []string{"a", "b", "a", "c", "a", "b"} {
+		if _, err := c.Get(kid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// c evicted b, b evicted c, a stayed in use
+	tests := []struct {
+		kid   string
+		calls int
+	}{
+		{"a", 1},
+		{"b", 2},
+		{"c", 1},
+	}
+	for _, tt := range tests {
+		if got := f.count(tt.kid); got != tt.calls {
+			t.Errorf("%s fetched %d times, want %d", tt.kid, got, tt.calls)
+		}
+	}
+	if c.Len() != 2 {
+		t.Fatalf("cache holds %d keys, want 2", c.Len())
+	}
+}
+
+func TestKeyCacheExpires(t *testing.T) {
+	f := &countingFetcher{}
+	c := NewKeyCache(0, 20*time.Millisecond, f.fetch)
+
+	first, _ := c.Get("a")
+	cached, _ := c.Get("a")
+	if cached != first {
+		t.Fatal("key was refetched before it expired")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if refetched, _ := c.Get("a"); refetched == first || f.count("a") != 2 {
+		t.Fatal("expired key was not refetched")
+	}
+}
+
+func TestKeyCacheSharesConcurrentFetches(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	c := NewKeyCache(0, 0, func(string) (*rsa.PublicKey, error) {
+		calls.Add(1)
+		<-release
+		return &rsa.PublicKey{N: big.NewInt(1), E: 65537}, nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Get("a"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("fetched %d times, want 1", calls.Load())
+	}
+}
+
+func TestKeyCacheReleasesWaitersOnPanic(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	c := NewKeyCache(0, 0, func(string) (*rsa.PublicKey, error) {
+		close(started)
+		<-release
+		panic("fetcher bug")
+	})
+
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = c.Get("a")
+	}()
+	<-started
+
+	waiter := make(chan error)
+	go func() {
+		_, err := c.Get("a")
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, errFetchPanicked) {
+			t.Fatalf("got %v, want %v", err, errFetchPanicked)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiter blocked after the fetcher panicked")
+	}
+}
+
+func TestKeyCacheInvalidateDuringFetch(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	f := &countingFetcher{}
+	c := NewKeyCache(0, 0, func(kid string) (*rsa.PublicKey, error) {
+		if f.count(kid) == 0 {
+			close(started)
+			<-release
+		}
+		return f.fetch(kid)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = c.Get("a")
+	}()
+	<-started
+	c.Invalidate("a")
+	close(release)
+	<-done
+
+	if c.Len() != 0 {
+		t.Fatal("the invalidated fetch result was cached")
+	}
+	if _, err := c.Get("a"); err != nil || f.count("a") != 2 {
+		t.Fatal("the key was not fetched again after invalidation")
+	}
+}
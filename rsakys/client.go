@@ -0,0 +1,55 @@
+package rsakys
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures the client returned by NewClientWithCert
+type ClientOption func(*http.Client, *tls.Config)
+
+// WithClientTimeout limits the duration of each request, defaults to 30 seconds
+func WithClientTimeout(d time.Duration) ClientOption {
+	return func(c *http.Client, _ *tls.Config) {
+		c.Timeout = d
+	}
+}
+
+// WithServerName overrides the host name the server certificate is verified against
+func WithServerName(name string) ClientOption {
+	return func(_ *http.Client, cfg *tls.Config) {
+		cfg.ServerName = name
+	}
+}
+
+// NewClientWithCert returns an HTTP client authenticating with the certificate and RSA key in
+// certPath and keyPath, and trusting the CA certificates in caPath or the system roots if it is empty.
+// Mismatching or expired certificate and key pairs fail here instead of during the handshake.
+func NewClientWithCert(certPath, keyPath, caPath string, opts ...ClientOption) (*http.Client, error) {
+	cert, err := loadKeyPair(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := loadCertPool(caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = cfg
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(client, cfg)
+	}
+
+	return client, nil
+}
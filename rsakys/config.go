@@ -0,0 +1,91 @@
+package rsakys
+
+import (
+	"crypto/rand"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+)
+
+// Logger receives the warnings of the package, *log.Logger satisfies it
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Config holds the package wide policy, so an application can set it once instead of passing
+// options to every call. Each field names the functions consulting it.
+type Config struct {
+	// PrivateFormat is the private key encoding of RotateKeypair, GenerateKeypairIfNotExists,
+	// and Generator jobs without a Format, defaults to PKCS8
+	PrivateFormat Format
+	// PrivatePerm is the file mode of all written private keys and escrow bundles, defaults to 0600
+	PrivatePerm os.FileMode
+	// PublicPerm is the file mode of all written public keys, metadata sidecars, and manifests, defaults to 0644
+	PublicPerm os.FileMode
+	// MinBits is the floor below which no keys are generated, defaults to 2048
+	MinBits int
+	// Strict rejects read keys that fail Lint and makes LoadPrivate, LoadPublic, and the signing
+	// helpers refuse expired keys even if the expiry policy only asks for a warning
+	Strict bool
+	// Logger receives warnings, defaults to the standard logger
+	Logger Logger
+	// Rand is the entropy source of key generation, encryption, signing, and secret sharing,
+	// defaults to crypto/rand.Reader. Newer Go versions ignore custom sources in
+	// some crypto/rsa operations unless enabled via GODEBUG.
+	Rand io.Reader
+}
+
+var config atomic.Pointer[Config]
+
+func init() {
+	cfg := DefaultConfig()
+	config.Store(&cfg)
+}
+
+// DefaultConfig returns the configuration the package starts with
+func DefaultConfig() Config {
+	return Config{
+		PrivateFormat: PKCS8,
+		PrivatePerm:   0o600,
+		PublicPerm:    0o644,
+		MinBits:       Bits2048,
+		Logger:        log.Default(),
+		Rand:          rand.Reader,
+	}
+}
+
+// SetDefaults replaces the package wide configuration, zero fields take their default values.
+// A PrivateFormat other than PKCS1 or PKCS8 is replaced by the default as well.
+func SetDefaults(cfg Config) {
+	def := DefaultConfig()
+	if cfg.PrivateFormat != PKCS1 && cfg.PrivateFormat != PKCS8 {
+		cfg.PrivateFormat = def.PrivateFormat
+	}
+	if cfg.PrivatePerm == 0 {
+		cfg.PrivatePerm = def.PrivatePerm
+	}
+	if cfg.PublicPerm == 0 {
+		cfg.PublicPerm = def.PublicPerm
+	}
+	if cfg.MinBits == 0 {
+		cfg.MinBits = def.MinBits
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = def.Logger
+	}
+	if cfg.Rand == nil {
+		cfg.Rand = def.Rand
+	}
+
+	config.Store(&cfg)
+}
+
+// Defaults returns the current package wide configuration
+func Defaults() Config {
+	return *config.Load()
+}
+
+func currentConfig() *Config {
+	return config.Load()
+}
@@ -0,0 +1,112 @@
+package rsakys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withConfig applies cfg for the duration of a test
+func withConfig(t *testing.T, cfg Config) {
+	t.Helper()
+	prev := Defaults()
+	SetDefaults(cfg)
+	t.Cleanup(func() { SetDefaults(prev) })
+}
+
+// countingReader counts the bytes drawn from crypto/rand
+type countingReader struct {
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := rand.Read(p)
+	r.n += n
+
+	return n, err
+}
+
+func TestSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want Format
+	}{
+		{"unset format", Config{}, PKCS8},
+		{"PKCS1", Config{PrivateFormat: PKCS1}, PKCS1},
+		{"PKCS8", Config{PrivateFormat: PKCS8}, PKCS8},
+		{"public format", Config{PrivateFormat: PKIX}, PKCS8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withConfig(t, tt.cfg)
+
+			got := Defaults()
+			if got.PrivateFormat != tt.want {
+				t.Fatalf("got format %v, want %v", got.PrivateFormat, tt.want)
+			}
+			if got.PrivatePerm != 0o600 || got.PublicPerm != 0o644 || got.MinBits != Bits2048 || got.Rand == nil || got.Logger == nil {
+				t.Fatalf("zero fields were not defaulted: %+v", got)
+			}
+		})
+	}
+}
+
+func TestConfigAppliesToSecretSharing(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custodians := make([]*rsa.PrivateKey, 2)
+	for i := range custodians {
+		if custodians[i], err = GetPrivateKey(Bits2048); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &countingReader{}
+	withConfig(t, Config{Rand: r, PrivatePerm: 0o640})
+
+	if _, err := SplitKey(key, 3, 2); err != nil {
+		t.Fatal(err)
+	}
+	if r.n == 0 {
+		t.Fatal("SplitKey did not draw from Config.Rand")
+	}
+
+	bundle, err := EscrowKey(key, []*rsa.PublicKey{&custodians[0].PublicKey, &custodians[1].PublicKey}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "escrow.json")
+	if err := WriteEscrowBundle(path, bundle); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Fatalf("escrow bundle has mode %v, want %v", info.Mode().Perm(), os.FileMode(0o640))
+	}
+
+	read, err := ReadEscrowBundle(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares := make([]Share, len(custodians))
+	for i, c := range custodians {
+		if shares[i], err = read.DecryptShare(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recovered, err := read.Recover(shares)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(recovered, key) {
+		t.Fatal("recovered key does not match the escrowed key")
+	}
+}
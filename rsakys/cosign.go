@@ -0,0 +1,160 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// This file holds the encrypted private key format of cosign (sigstore): a PKCS8 key sealed with
+// nacl/secretbox under a scrypt derived key, stored as JSON inside a PEM block.
+
+const (
+	cosignPrivateType       = "ENCRYPTED SIGSTORE PRIVATE KEY"
+	cosignLegacyPrivateType = "ENCRYPTED COSIGN PRIVATE KEY"
+	cosignKDF               = "scrypt"
+	cosignCipher            = "nacl/secretbox"
+	cosignSaltSize          = 32
+	cosignNonceSize         = 24
+	cosignKeySize           = 32
+	// scrypt parameters of 'cosign generate-key-pair'
+	cosignScryptN = 32768
+	cosignScryptR = 8
+	cosignScryptP = 1
+	// upper bound for N read from files, guards against memory exhaustion
+	cosignMaxScryptN = 1 << 20
+)
+
+var (
+	errCosignFormat  = errors.New("unsupported cosign key format")
+	errCosignDecrypt = errors.New("unable to decrypt cosign key, wrong password or corrupted file")
+)
+
+type cosignKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// GetCosignPrivateKeyString returns a given RSA private key as cosign encrypted PEM block,
+// readable by 'cosign sign --key'
+func GetCosignPrivateKeyString(privateKey *rsa.PrivateKey, password []byte) ([]byte, error) {
+	block, err := cosignEncryptedBlock(privateKey, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// WriteCosignPrivateKey writes a given RSA private key as cosign encrypted PEM block to disc
+func WriteCosignPrivateKey(privateKey *rsa.PrivateKey, path string, password []byte, opts ...WriteOption) error {
+	block, err := cosignEncryptedBlock(privateKey, password)
+	if err != nil {
+		return err
+	}
+
+	return writeKeyFile(path, currentConfig().PrivatePerm, pem.EncodeToMemory(block), newWriteOptions(opts))
+}
+
+// ReadCosignPrivate reads a cosign encrypted private key PEM file holding an RSA key, e.g. a 'cosign.key'
+// imported with 'cosign import-key-pair', and returns the private key struct.
+// Keys from 'cosign generate-key-pair' are ECDSA and rejected.
+func ReadCosignPrivate(path string, password []byte) (*rsa.PrivateKey, error) {
+	cntnt, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(cntnt)
+	if block == nil {
+		return nil, errParse
+	}
+	if block.Type != cosignPrivateType && block.Type != cosignLegacyPrivateType {
+		return nil, errCosignFormat
+	}
+
+	der, err := cosignDecrypt(block.Bytes, password)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errCosignDecrypt
+	}
+
+	return asKey[*rsa.PrivateKey](key)
+}
+
+func cosignEncryptedBlock(privateKey *rsa.PrivateKey, password []byte) (*pem.Block, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var k cosignKey
+	k.KDF.Name = cosignKDF
+	k.KDF.Params.N, k.KDF.Params.R, k.KDF.Params.P = cosignScryptN, cosignScryptR, cosignScryptP
+	k.KDF.Salt = make([]byte, cosignSaltSize)
+	k.Cipher.Name = cosignCipher
+	k.Cipher.Nonce = make([]byte, cosignNonceSize)
+	if _, err := io.ReadFull(currentConfig().Rand, k.KDF.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(currentConfig().Rand, k.Cipher.Nonce); err != nil {
+		return nil, err
+	}
+
+	secret, err := scrypt.Key(password, k.KDF.Salt, cosignScryptN, cosignScryptR, cosignScryptP, cosignKeySize)
+	if err != nil {
+		return nil, err
+	}
+	k.Ciphertext = secretbox.Seal(nil, der, (*[cosignNonceSize]byte)(k.Cipher.Nonce), (*[cosignKeySize]byte)(secret))
+
+	data, err := json.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pem.Block{Type: cosignPrivateType, Bytes: data}, nil
+}
+
+func cosignDecrypt(data, password []byte) ([]byte, error) {
+	var k cosignKey
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, errCosignFormat
+	}
+	p := k.KDF.Params
+	if k.KDF.Name != cosignKDF || k.Cipher.Name != cosignCipher || len(k.Cipher.Nonce) != cosignNonceSize ||
+		p.N <= 1 || p.N > cosignMaxScryptN || p.R <= 0 || p.P <= 0 {
+		return nil, errCosignFormat
+	}
+
+	secret, err := scrypt.Key(password, k.KDF.Salt, p.N, p.R, p.P, cosignKeySize)
+	if err != nil {
+		return nil, errCosignFormat
+	}
+	der, ok := secretbox.Open(nil, k.Ciphertext, (*[cosignNonceSize]byte)(k.Cipher.Nonce), (*[cosignKeySize]byte)(secret))
+	if !ok {
+		return nil, errCosignDecrypt
+	}
+
+	return der, nil
+}
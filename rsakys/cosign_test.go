@@ -0,0 +1,141 @@
+package rsakys
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// sealCosign seals der like cosign but with cheap scrypt parameters, mutate may alter the result
+func sealCosign(t *testing.T, der, password []byte, blockType string, mutate func(k *cosignKey)) []byte {
+	t.Helper()
+	var k cosignKey
+	k.KDF.Name = cosignKDF
+	k.KDF.Params.N, k.KDF.Params.R, k.KDF.Params.P = 1024, 8, 1
+	k.KDF.Salt = make([]byte, cosignSaltSize)
+	k.Cipher.Name = cosignCipher
+	k.Cipher.Nonce = make([]byte, cosignNonceSize)
+	if _, err := rand.Read(k.Cipher.Nonce); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := scrypt.Key(password, k.KDF.Salt, k.KDF.Params.N, k.KDF.Params.R, k.KDF.Params.P, cosignKeySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.Ciphertext = secretbox.Seal(nil, der, (*[cosignNonceSize]byte)(k.Cipher.Nonce), (*[cosignKeySize]byte)(secret))
+	if mutate != nil {
+		mutate(&k)
+	}
+
+	data, err := json.Marshal(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data})
+}
+
+func TestCosignRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	password := []byte("correct horse")
+	dir := t.TempDir()
+
+	encoded, err := GetCosignPrivateKeyString(key, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(encoded)
+	if block == nil || block.Type != cosignPrivateType {
+		t.Fatalf("unexpected PEM block:\n%s", encoded)
+	}
+	fromString := filepath.Join(dir, "string.key")
+	mustWrite(t, fromString, string(encoded), 0o600)
+
+	written := filepath.Join(dir, "cosign.key")
+	if err := WriteCosignPrivateKey(key, written, password); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{fromString, written} {
+		got, err := ReadCosignPrivate(path, password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equal(got, key) {
+			t.Fatalf("%s: key differs", path)
+		}
+		if _, err := ReadCosignPrivate(path, []byte("wrong")); !errors.Is(err, errCosignDecrypt) {
+			t.Fatalf("expected errCosignDecrypt, got %v", err)
+		}
+	}
+}
+
+func TestReadCosignPrivateFormats(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	password := []byte("pw")
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"sigstore", sealCosign(t, der, password, cosignPrivateType, nil), nil},
+		{"legacy cosign", sealCosign(t, der, password, cosignLegacyPrivateType, nil), nil},
+		{"no PEM", []byte("cosign"), errParse},
+		{"other PEM type", sealCosign(t, der, password, "ENCRYPTED PRIVATE KEY", nil), errCosignFormat},
+		{"not JSON", pem.EncodeToMemory(&pem.Block{Type: cosignPrivateType, Bytes: []byte("{")}), errCosignFormat},
+		{"ecdsa key", sealCosign(t, ecDER, password, cosignPrivateType, nil), ErrUnexpectedKeyType},
+		{"not PKCS8", sealCosign(t, []byte("garbage"), password, cosignPrivateType, nil), errCosignDecrypt},
+		{"kdf", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.KDF.Name = "argon2" }), errCosignFormat},
+		{"cipher", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.Cipher.Name = "aes" }), errCosignFormat},
+		{"nonce size", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.Cipher.Nonce = k.Cipher.Nonce[1:] }), errCosignFormat},
+		{"excessive N", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.KDF.Params.N = cosignMaxScryptN * 2 }), errCosignFormat},
+		{"N not a power of two", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.KDF.Params.N = 1000 }), errCosignFormat},
+		{"zero r", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.KDF.Params.R = 0 }), errCosignFormat},
+		{"ciphertext", sealCosign(t, der, password, cosignPrivateType, func(k *cosignKey) { k.Ciphertext[0] ^= 1 }), errCosignDecrypt},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cosign.key")
+			if err := os.WriteFile(path, tt.data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadCosignPrivate(path, password)
+			if tt.err == nil {
+				if err != nil || !Equal(got, key) {
+					t.Fatalf("got %v", err)
+				}
+			} else if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	delegationVersion = "rsakys-delegation-1"
+	// ScopeAll grants every scope of the issuer
+	ScopeAll = "*"
+)
+
+// Errors of delegation verification
+var (
+	ErrDelegationSignature = errors.New("delegation signature is invalid")
+	ErrDelegationExpired   = errors.New("delegation is expired or not yet valid")
+	ErrDelegationScope     = errors.New("delegation does not grant the scope")
+)
+
+// Delegation authorizes a short-lived key, e.g. of a build agent, to sign for the given scopes
+// on behalf of the issuing key until NotAfter
+type Delegation struct {
+	Issuer    string    `json:"issuer"`
+	Key       []byte    `json:"key"`
+	Scopes    []string  `json:"scopes"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	Signature []byte    `json:"signature"`
+}
+
+// DelegatedSignature is a signature made by a delegated key together with the chain of
+// delegations leading to it from the root key
+type DelegatedSignature struct {
+	Chain     []Delegation `json:"chain"`
+	Signature []byte       `json:"signature"`
+}
+
+// Delegate lets issuer authorize delegate for the given scopes from now on for ttl.
+// A delegated key may delegate further, but only a subset of its scopes and within its own validity.
+func Delegate(issuer *rsa.PrivateKey, delegate *rsa.PublicKey, scopes []string, ttl time.Duration) (*Delegation, error) {
+	if err := checkSigningKey(issuer.N); err != nil {
+		return nil, err
+	}
+	fp, err := Fingerprint(&issuer.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKIXPublicKey(delegate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	d := &Delegation{
+		Issuer:    fp,
+		Key:       der,
+		Scopes:    slices.Clone(scopes),
+		NotBefore: now,
+		NotAfter:  now.Add(ttl).Truncate(time.Second),
+	}
+	digest := d.digest()
+	if d.Signature, err = rsa.SignPSS(currentConfig().Rand, issuer, crypto.SHA256, digest[:], nil); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// SignDelegated signs msg with a delegated key and attaches the delegation chain, root delegation first
+func SignDelegated(privateKey *rsa.PrivateKey, chain []Delegation, msg []byte) (*DelegatedSignature, error) {
+	if err := checkSigningKey(privateKey.N); err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(msg)
+	sig, err := rsa.SignPSS(currentConfig().Rand, privateKey, crypto.SHA256, digest[:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DelegatedSignature{Chain: slices.Clone(chain), Signature: sig}, nil
+}
+
+// VerifyDelegated checks that msg was signed by a key the root key delegated scope to, through a chain
+// of currently valid delegations
+func VerifyDelegated(root *rsa.PublicKey, scope string, msg []byte, ds *DelegatedSignature) error {
+	key, err := VerifyChain(root, ds.Chain, scope, time.Now())
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(msg)
+	if err := rsa.VerifyPSS(key, crypto.SHA256, digest[:], ds.Signature, nil); err != nil {
+		return ErrDelegationSignature
+	}
+
+	return nil
+}
+
+// VerifyChain walks a delegation chain from root and returns the final delegated key
+// if every delegation is signed by its predecessor, valid at the given time, and grants scope
+func VerifyChain(root *rsa.PublicKey, chain []Delegation, scope string, at time.Time) (*rsa.PublicKey, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("%w: empty chain", ErrDelegationSignature)
+	}
+
+	issuer := root
+	for i := range chain {
+		key, err := chain[i].verify(issuer, scope, at)
+		if err != nil {
+			return nil, fmt.Errorf("delegation %d: %w", i, err)
+		}
+		issuer = key
+	}
+
+	return issuer, nil
+}
+
+// Grants reports whether the delegation covers scope
+func (d *Delegation) Grants(scope string) bool {
+	return slices.Contains(d.Scopes, ScopeAll) || slices.Contains(d.Scopes, scope)
+}
+
+func (d *Delegation) verify(issuer *rsa.PublicKey, scope string, at time.Time) (*rsa.PublicKey, error) {
+	fp, err := Fingerprint(issuer)
+	if err != nil {
+		return nil, err
+	}
+	digest := d.digest()
+	if d.Issuer != fp || rsa.VerifyPSS(issuer, crypto.SHA256, digest[:], d.Signature, nil) != nil {
+		return nil, ErrDelegationSignature
+	}
+	if at.Before(d.NotBefore) || !at.Before(d.NotAfter) {
+		return nil, fmt.Errorf("%w: valid from %s to %s", ErrDelegationExpired, d.NotBefore, d.NotAfter)
+	}
+	if !d.Grants(scope) {
+		return nil, fmt.Errorf("%w: %q", ErrDelegationScope, scope)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(d.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	return asKey[*rsa.PublicKey](key)
+}
+
+// digest hashes a canonical encoding of the signed fields, independent of the JSON formatting
+func (d *Delegation) digest() [sha256.Size]byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n%s\n%x\n%d\n%d\n", delegationVersion, d.Issuer, d.Key, d.NotBefore.Unix(), d.NotAfter.Unix())
+	for _, s := range d.Scopes {
+		fmt.Fprintf(&buf, "%q\n", s)
+	}
+
+	return sha256.Sum256(buf.Bytes())
+}
@@ -0,0 +1,164 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDelegationChain(t *testing.T) {
+	root, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := Delegate(root, &agent.PublicKey, []string{"deploy", "release"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Delegate(agent, &job.PublicKey, []string{"deploy"}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := []Delegation{*first, *second}
+
+	msg := []byte("artifact")
+	ds, err := SignDelegated(job, chain, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the signature survives its JSON form
+	data, err := json.Marshal(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed DelegatedSignature
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		root  *rsa.PublicKey
+		scope string
+		msg   []byte
+		ds    *DelegatedSignature
+		err   error
+	}{
+		{"valid", &root.PublicKey, "deploy", msg, &parsed, nil},
+		{"scope not delegated further", &root.PublicKey, "release", msg, &parsed, ErrDelegationScope},
+		{"unknown scope", &root.PublicKey, "admin", msg, &parsed, ErrDelegationScope},
+		{"other root", &agent.PublicKey, "deploy", msg, &parsed, ErrDelegationSignature},
+		{"other message", &root.PublicKey, "deploy", []byte("artefact"), &parsed, ErrDelegationSignature},
+		{"empty chain", &root.PublicKey, "deploy", msg, &DelegatedSignature{Signature: ds.Signature}, ErrDelegationSignature},
+		{"reordered chain", &root.PublicKey, "deploy", msg, &DelegatedSignature{Chain: []Delegation{*second, *first}, Signature: ds.Signature}, ErrDelegationSignature},
+		{"shortened chain", &root.PublicKey, "deploy", msg, &DelegatedSignature{Chain: chain[:1], Signature: ds.Signature}, ErrDelegationSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyDelegated(tt.root, tt.scope, tt.msg, tt.ds); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestDelegationValidity(t *testing.T) {
+	root, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Delegate(root, &agent.PublicKey, []string{ScopeAll}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Grants("anything") {
+		t.Fatal("ScopeAll does not grant every scope")
+	}
+	chain := []Delegation{*d}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		err  error
+	}{
+		{"at start", d.NotBefore, nil},
+		{"before start", d.NotBefore.Add(-time.Second), ErrDelegationExpired},
+		{"before end", d.NotAfter.Add(-time.Second), nil},
+		{"at end", d.NotAfter, ErrDelegationExpired},
+		{"after end", d.NotAfter.Add(time.Hour), ErrDelegationExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := VerifyChain(&root.PublicKey, chain, "deploy", tt.at)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err == nil && !key.Equal(&agent.PublicKey) {
+				t.Fatal("chain returned another key")
+			}
+		})
+	}
+}
+
+func TestDelegationTampering(t *testing.T) {
+	root, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	agent, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := Delegate(root, &agent.PublicKey, []string{"deploy"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherDelegation, err := Delegate(root, &other.PublicKey, []string{"deploy"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(d *Delegation)
+	}{
+		{"scopes", func(d *Delegation) { d.Scopes = append(d.Scopes, ScopeAll) }},
+		{"not after", func(d *Delegation) { d.NotAfter = d.NotAfter.Add(24 * time.Hour) }},
+		{"not before", func(d *Delegation) { d.NotBefore = d.NotBefore.Add(-time.Hour) }},
+		{"key", func(d *Delegation) { d.Key = otherDelegation.Key }},
+		{"issuer", func(d *Delegation) { d.Issuer = otherDelegation.Issuer + "x" }},
+		{"signature", func(d *Delegation) { d.Signature[0] ^= 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := *d
+			tampered.Scopes = slices.Clone(d.Scopes)
+			tampered.Signature = slices.Clone(d.Signature)
+			tt.tamper(&tampered)
+			if _, err := VerifyChain(&root.PublicKey, []Delegation{tampered}, "deploy", time.Now()); !errors.Is(err, ErrDelegationSignature) {
+				t.Fatalf("expected ErrDelegationSignature, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"os"
+	"path/filepath"
+)
+
+// ReadAllPrivateKeys reads all '.pem' files of a directory and returns their private key structs
+// keyed by file name without suffix. Files that do not hold an RSA private key are skipped.
+func ReadAllPrivateKeys(dir string) (map[string]*rsa.PrivateKey, error) {
+	keys := make(map[string]*rsa.PrivateKey)
+	err := scanDir(dir, privateSuffix, func(name string, cntnt []byte) {
+		if key, err := parsePrivate(cntnt); err == nil {
+			keys[name] = key
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// ReadAllPublicKeys reads all '.pub' files of a directory and returns their public key structs
+// keyed by file name without suffix. Files that do not hold an RSA public key are skipped.
+func ReadAllPublicKeys(dir string) (map[string]*rsa.PublicKey, error) {
+	keys := make(map[string]*rsa.PublicKey)
+	err := scanDir(dir, publicSuffix, func(name string, cntnt []byte) {
+		if key, err := parsePublic(cntnt); err == nil {
+			keys[name] = key
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func scanDir(dir, suffix string, fn func(name string, cntnt []byte)) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if !hasSuffix(name, suffix) {
+			continue
+		}
+
+		p := filepath.Join(dir, name)
+		info, err := os.Stat(p)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		cntnt, err := readFile(p)
+		if err != nil {
+			return err
+		}
+		fn(trimSuffix(name, suffix), cntnt)
+	}
+
+	return nil
+}
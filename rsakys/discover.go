@@ -0,0 +1,153 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+)
+
+// KeyKind classifies discovered key material
+type KeyKind uint
+
+// Kinds of discovered key material
+const (
+	KindUnknown KeyKind = iota
+	KindPrivate
+	KindPublic
+)
+
+// String returns the name of the kind
+func (k KeyKind) String() string {
+	switch k {
+	case KindPrivate:
+		return "private"
+	case KindPublic:
+		return "public"
+	default:
+		return "unknown"
+	}
+}
+
+// DiscoveredKey is a file matched by Discover together with its detected kind and format.
+// For private keys PublicKey holds the public key part, for unknown files Err holds the reason.
+type DiscoveredKey struct {
+	Path       string
+	Kind       KeyKind
+	Format     Format
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+	KeyID      string
+	Err        error
+}
+
+// Discover expands a glob pattern, e.g. '/etc/myapp/keys/*.pub',
+// and classifies every matched regular file by detecting its key format.
+// File names are matched ignoring case on case-insensitive platforms, e.g. 'KEY.PEM' for '*.pem' on Windows.
+func Discover(pattern string) ([]DiscoveredKey, error) {
+	matches, err := globFiles(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []DiscoveredKey
+	for _, m := range matches {
+		info, err := os.Stat(fixPath(m))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+
+		dk := DiscoveredKey{Path: m}
+		cntnt, err := readFile(m)
+		if err != nil {
+			dk.Err = err
+		} else {
+			detectKey(cntnt, &dk)
+		}
+		found = append(found, dk)
+	}
+
+	return found, nil
+}
+
+// globFiles expands the directory part of pattern with filepath.Glob and matches the file names
+// of these directories with matchName, as filepath.Glob is case-sensitive everywhere
+func globFiles(pattern string) ([]string, error) {
+	dirPattern, filePattern := filepath.Split(pattern)
+	if _, err := filepath.Match(filePattern, ""); err != nil {
+		return nil, err
+	}
+
+	dirs := []string{"."}
+	if dirPattern != "" {
+		var err error
+		if dirs, err = filepath.Glob(filepath.Clean(dirPattern)); err != nil {
+			return nil, err
+		}
+	}
+
+	var matches []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(fixPath(dir))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if ok, _ := matchName(filePattern, e.Name()); ok {
+				matches = append(matches, filepath.Join(dir, e.Name()))
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+func detectKey(data []byte, dk *DiscoveredKey) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		dk.Err = errParse
+		return
+	}
+	dk.KeyID = block.Headers[keyIDHeader]
+
+	switch block.Type {
+	case privateType, "PRIVATE KEY":
+		if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			dk.setPrivate(key, PKCS1)
+			return
+		}
+		if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+			if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+				dk.setPrivate(rsaKey, PKCS8)
+				return
+			}
+		}
+	case publicType, "PUBLIC KEY":
+		if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+			dk.setPublic(key, PKCS1)
+			return
+		}
+		if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+			if rsaKey, ok := key.(*rsa.PublicKey); ok {
+				dk.setPublic(rsaKey, PKIX)
+				return
+			}
+		}
+	}
+
+	dk.Err = errParse
+}
+
+func (dk *DiscoveredKey) setPrivate(key *rsa.PrivateKey, format Format) {
+	dk.Kind = KindPrivate
+	dk.Format = format
+	dk.PrivateKey = key
+	dk.PublicKey = &key.PublicKey
+}
+
+func (dk *DiscoveredKey) setPublic(key *rsa.PublicKey, format Format) {
+	dk.Kind = KindPublic
+	dk.Format = format
+	dk.PublicKey = key
+}
@@ -0,0 +1,64 @@
+package rsakys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"a", "b"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := WritePKCS8PrivateKey(key, filepath.Join(root, dir, "key.pem")); err != nil {
+			t.Fatal(err)
+		}
+		if err := WritePKIXPublicKey(&key.PublicKey, filepath.Join(root, dir, "key.pub")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "junk.pem"), []byte("junk"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pattern string
+		kinds   map[string]KeyKind
+	}{
+		{filepath.Join(root, "a", "*.pem"), map[string]KeyKind{"a/key.pem": KindPrivate, "a/junk.pem": KindUnknown}},
+		{filepath.Join(root, "*", "*.pub"), map[string]KeyKind{"a/key.pub": KindPublic, "b/key.pub": KindPublic}},
+		{filepath.Join(root, "b", "key.*"), map[string]KeyKind{"b/key.pem": KindPrivate, "b/key.pub": KindPublic}},
+		{filepath.Join(root, "c", "*.pem"), map[string]KeyKind{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			found, err := Discover(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(found) != len(tt.kinds) {
+				t.Fatalf("found %d files, want %d", len(found), len(tt.kinds))
+			}
+			for _, dk := range found {
+				rel, _ := filepath.Rel(root, dk.Path)
+				want, ok := tt.kinds[filepath.ToSlash(rel)]
+				if !ok || dk.Kind != want {
+					t.Errorf("%s: got kind %v, want %v", rel, dk.Kind, want)
+				}
+				if dk.Kind != KindUnknown && !EqualPublic(dk.PublicKey, &key.PublicKey) {
+					t.Errorf("%s: wrong public key", rel)
+				}
+			}
+		})
+	}
+
+	if _, err := Discover(filepath.Join(root, "[")); err == nil {
+		t.Fatal("malformed pattern was accepted")
+	}
+}
@@ -0,0 +1,176 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// DNSKEY flags, RFC 4034 section 2.1.1, a key signing key sets both
+const (
+	DNSKEYFlagZone uint16 = 256
+	DNSKEYFlagSEP  uint16 = 1
+)
+
+// DNSSEC algorithm numbers of RSA keys, RFC 3110 and RFC 5702
+const (
+	DNSSECRSASHA1          uint8 = 5
+	DNSSECRSASHA1NSEC3SHA1 uint8 = 7
+	DNSSECRSASHA256        uint8 = 8
+	DNSSECRSASHA512        uint8 = 10
+)
+
+const (
+	dnssecProtocol = 3
+	dsDigestSHA256 = 2
+	maxDNSSECBits  = 4096
+)
+
+var (
+	errDNSSECAlgorithm = errors.New("not an RSA DNSSEC algorithm")
+	errDNSSECKeySize   = errors.New("key size is not allowed for DNSSEC")
+	errOwnerName       = errors.New("invalid DNS owner name")
+)
+
+// DNSKEY is the RDATA of a DNSKEY resource record
+type DNSKEY struct {
+	Flags     uint16
+	Protocol  uint8
+	Algorithm uint8
+	// PublicKey is the key in RFC 3110 format, exponent length, exponent, and modulus
+	PublicKey []byte
+}
+
+// ExportDNSKEY returns a public key as DNSKEY record with the given flags, e.g. DNSKEYFlagZone
+// for a zone signing key or DNSKEYFlagZone|DNSKEYFlagSEP for a key signing key, and algorithm
+func ExportDNSKEY(publicKey *rsa.PublicKey, flags uint16, algorithm uint8) (*DNSKEY, error) {
+	switch algorithm {
+	case DNSSECRSASHA1, DNSSECRSASHA1NSEC3SHA1, DNSSECRSASHA256, DNSSECRSASHA512:
+	default:
+		return nil, fmt.Errorf("%w: %d", errDNSSECAlgorithm, algorithm)
+	}
+	if publicKey.N.BitLen() > maxDNSSECBits {
+		return nil, fmt.Errorf("%w: %d > %d bits", errDNSSECKeySize, publicKey.N.BitLen(), maxDNSSECBits)
+	}
+
+	// RFC 3110 section 2, exponents longer than 255 bytes are prefixed with a zero and a 16 bit length
+	var buf bytes.Buffer
+	exp := big.NewInt(int64(publicKey.E)).Bytes()
+	if len(exp) > 255 {
+		buf.WriteByte(0)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(len(exp))))
+	} else {
+		buf.WriteByte(byte(len(exp)))
+	}
+	buf.Write(exp)
+	buf.Write(publicKey.N.Bytes())
+
+	return &DNSKEY{
+		Flags:     flags,
+		Protocol:  dnssecProtocol,
+		Algorithm: algorithm,
+		PublicKey: buf.Bytes(),
+	}, nil
+}
+
+// RDATA returns the wire format of the record data
+func (k *DNSKEY) RDATA() []byte {
+	rdata := binary.BigEndian.AppendUint16(nil, k.Flags)
+	rdata = append(rdata, k.Protocol, k.Algorithm)
+
+	return append(rdata, k.PublicKey...)
+}
+
+// KeyTag returns the key tag referencing the key in RRSIG and DS records, RFC 4034 appendix B
+func (k *DNSKEY) KeyTag() uint16 {
+	var ac uint32
+	for i, b := range k.RDATA() {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xffff
+
+	return uint16(ac & 0xffff)
+}
+
+// String returns the record data in presentation format, e.g. '257 3 8 AwEAAb...'
+func (k *DNSKEY) String() string {
+	return fmt.Sprintf("%d %d %d %s", k.Flags, k.Protocol, k.Algorithm, base64.StdEncoding.EncodeToString(k.PublicKey))
+}
+
+// RR renders the DNSKEY resource record of owner, e.g. 'example.com. 3600 IN DNSKEY 257 3 8 AwEAAb...',
+// ready to be added to a zone file
+func (k *DNSKEY) RR(owner string, ttl uint32) (string, error) {
+	if _, err := ownerWireName(owner); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %d IN DNSKEY %s", fqdn(owner), ttl, k), nil
+}
+
+// DS renders the SHA-256 DS resource record of owner to be published in the parent zone,
+// e.g. 'example.com. 3600 IN DS 60485 8 2 D4B7D5...'
+func (k *DNSKEY) DS(owner string, ttl uint32) (string, error) {
+	digest, err := k.DSDigest(owner)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s %d IN DS %d %d %d %s", fqdn(owner), ttl, k.KeyTag(), k.Algorithm, dsDigestSHA256,
+		strings.ToUpper(hex.EncodeToString(digest))), nil
+}
+
+// DSDigest returns the SHA-256 digest of the owner name and record data, RFC 4509
+func (k *DNSKEY) DSDigest(owner string) ([]byte, error) {
+	name, err := ownerWireName(owner)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(append(name, k.RDATA()...))
+
+	return sum[:], nil
+}
+
+// ownerWireName returns the canonical, lower case wire format of a domain name, RFC 4034 section 6.2.
+// Escaped characters in presentation format are not supported.
+func ownerWireName(owner string) ([]byte, error) {
+	name := strings.TrimSuffix(strings.ToLower(owner), ".")
+	if owner == "." {
+		return []byte{0}, nil
+	}
+	if name == "" || strings.Contains(name, "\\") {
+		return nil, fmt.Errorf("%w: %q", errOwnerName, owner)
+	}
+
+	var wire []byte
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 {
+			return nil, fmt.Errorf("%w: %q", errOwnerName, owner)
+		}
+		wire = append(append(wire, byte(len(label))), label...)
+	}
+	wire = append(wire, 0)
+	if len(wire) > 255 {
+		return nil, fmt.Errorf("%w: %q", errOwnerName, owner)
+	}
+
+	return wire, nil
+}
+
+func fqdn(owner string) string {
+	if strings.HasSuffix(owner, ".") {
+		return owner
+	}
+
+	return owner + "."
+}
@@ -0,0 +1,157 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// rfcKey builds the public key of an RFC 3110 key with a one byte exponent length
+func rfcKey(t *testing.T, b64 string) *rsa.PublicKey {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := int(data[0])
+
+	return &rsa.PublicKey{
+		E: int(new(big.Int).SetBytes(data[1 : 1+n]).Int64()),
+		N: new(big.Int).SetBytes(data[1+n:]),
+	}
+}
+
+func TestDNSKEYVectors(t *testing.T) {
+	tests := []struct {
+		name      string
+		flags     uint16
+		algorithm uint8
+		key       string
+		tag       uint16
+	}{
+		{
+			// RFC 4034 section 5.4
+			name:      "rsasha1",
+			flags:     DNSKEYFlagZone,
+			algorithm: DNSSECRSASHA1,
+			key: "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvx" +
+				"egXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw==",
+			tag: 60485,
+		},
+		{
+			// RFC 5702 section 6.1
+			name:      "rsasha256",
+			flags:     DNSKEYFlagZone,
+			algorithm: DNSSECRSASHA256,
+			key:       "AwEAAcFcGsaxxdgiuuGmCkVImy4h99CqT7jwY3pexPGcnUFtR2Fh36BponcwtkZ4cAgtvd4Qs8PkxUdp6p/DlUmObdk=",
+			tag:       9033,
+		},
+		{
+			// RFC 5702 section 6.2
+			name:      "rsasha512",
+			flags:     DNSKEYFlagZone,
+			algorithm: DNSSECRSASHA512,
+			key: "AwEAAdHoNTOW+et86KuJOWRDp1pndvwb6Y83nSVXXyLA3DLroROUkN6X0O6pnWnjJQujX/AyhqFDxj13tOnD9u/1kTg7cV6r" +
+				"klMrZDtJCQ5PCl/D7QNPsgVsMu1J2Q8gpMpztNFLpPBz1bWXjDtaR7ZQBlZ3PFY12ZTSncorffcGmhOL",
+			tag: 3740,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := ExportDNSKEY(rfcKey(t, tt.key), tt.flags, tt.algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := base64.StdEncoding.EncodeToString(k.PublicKey); got != tt.key {
+				t.Fatalf("got key %s", got)
+			}
+			if got := k.KeyTag(); got != tt.tag {
+				t.Fatalf("got key tag %d, want %d", got, tt.tag)
+			}
+		})
+	}
+}
+
+func TestDNSKEYDS(t *testing.T) {
+	// RFC 4509 section 2.3
+	k, err := ExportDNSKEY(rfcKey(t, "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvx"+
+		"egXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="), DNSKEYFlagZone, DNSSECRSASHA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const digest = "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"
+
+	for _, owner := range []string{"dskey.example.com.", "dskey.example.com", "DSKEY.Example.COM."} {
+		got, err := k.DSDigest(owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want, _ := hex.DecodeString(digest); !bytes.Equal(got, want) {
+			t.Fatalf("%s: got %X", owner, got)
+		}
+	}
+
+	ds, err := k.DS("dskey.example.com", 86400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "dskey.example.com. 86400 IN DS 60485 5 2 " + digest; ds != want {
+		t.Fatalf("got %s", ds)
+	}
+	rr, err := k.RR("dskey.example.com.", 86400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "dskey.example.com. 86400 IN DNSKEY 256 3 5 AQOeiiR0"; !strings.HasPrefix(rr, want) {
+		t.Fatalf("got %s", rr)
+	}
+}
+
+func TestExportDNSKEYErrors(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large := &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), maxDNSSECBits), E: 65537}
+
+	if _, err := ExportDNSKEY(&key.PublicKey, DNSKEYFlagZone, 13); !errors.Is(err, errDNSSECAlgorithm) {
+		t.Fatalf("expected errDNSSECAlgorithm, got %v", err)
+	}
+	if _, err := ExportDNSKEY(large, DNSKEYFlagZone, DNSSECRSASHA256); !errors.Is(err, errDNSSECKeySize) {
+		t.Fatalf("expected errDNSSECKeySize, got %v", err)
+	}
+
+	k, err := ExportDNSKEY(&key.PublicKey, DNSKEYFlagZone|DNSKEYFlagSEP, DNSSECRSASHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(k.String(), "257 3 8 AwEAA") {
+		t.Fatalf("got %s", k)
+	}
+
+	tests := []struct {
+		owner string
+		ok    bool
+	}{
+		{".", true},
+		{"example.com", true},
+		{"", false},
+		{"..", false},
+		{"a..com", false},
+		{`a\.b.com`, false},
+		{strings.Repeat("a", 63) + ".com", true},
+		{strings.Repeat("a", 64) + ".com", false},
+		{strings.Repeat(strings.Repeat("a", 63)+".", 4), false},
+	}
+	for _, tt := range tests {
+		_, err := k.DS(tt.owner, 3600)
+		if tt.ok != (err == nil) || (!tt.ok && !errors.Is(err, errOwnerName)) {
+			t.Fatalf("%q: got %v", tt.owner, err)
+		}
+	}
+}
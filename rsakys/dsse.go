@@ -0,0 +1,99 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+// ErrEnvelopeThreshold is returned when fewer distinct keys than required signed an envelope
+var ErrEnvelopeThreshold = errors.New("envelope is not signed by enough trusted keys")
+
+// Envelope is a DSSE (Dead Simple Signing Envelope), e.g. carrying an in-toto attestation
+type Envelope struct {
+	PayloadType string              `json:"payloadType"`
+	Payload     []byte              `json:"payload"`
+	Signatures  []EnvelopeSignature `json:"signatures"`
+}
+
+// EnvelopeSignature is a single RSA-PSS signature of an envelope, KeyID is the thumbprint of the signing key
+type EnvelopeSignature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// PAE returns the DSSE pre-authentication encoding of a payload,
+// 'DSSEv1 <len(type)> <type> <len(payload)> <payload>'
+func PAE(payloadType string, payload []byte) []byte {
+	return append([]byte(fmt.Sprintf("DSSEv1 %d %s %d ", len(payloadType), payloadType, len(payload))), payload...)
+}
+
+// SignEnvelope wraps payload in a DSSE envelope signed by all given keys,
+// e.g. payloadType 'application/vnd.in-toto+json' for in-toto statements
+func SignEnvelope(payloadType string, payload []byte, privateKeys ...*rsa.PrivateKey) (*Envelope, error) {
+	e := &Envelope{PayloadType: payloadType, Payload: payload}
+	for _, k := range privateKeys {
+		if err := e.Sign(k); err != nil {
+			return nil, err
+		}
+	}
+
+	return e, nil
+}
+
+// Sign adds a signature of privateKey to the envelope
+func (e *Envelope) Sign(privateKey *rsa.PrivateKey) error {
+	if err := checkSigningKey(privateKey.N); err != nil {
+		return err
+	}
+	kid, err := Thumbprint(&privateKey.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(PAE(e.PayloadType, e.Payload))
+	sig, err := rsa.SignPSS(currentConfig().Rand, privateKey, crypto.SHA256, digest[:], nil)
+	if err != nil {
+		return err
+	}
+	e.Signatures = append(e.Signatures, EnvelopeSignature{KeyID: kid, Sig: sig})
+
+	return nil
+}
+
+// Verify checks that at least threshold of the given keys made a valid signature of the envelope
+// and returns the payload. A threshold of 0 or less requires one signature.
+func (e *Envelope) Verify(threshold int, publicKeys ...*rsa.PublicKey) ([]byte, error) {
+	threshold = max(threshold, 1)
+	digest := sha256.Sum256(PAE(e.PayloadType, e.Payload))
+
+	// the same key passed twice must not count twice
+	verified := make(map[string]bool, len(publicKeys))
+	for _, k := range publicKeys {
+		kid, err := Thumbprint(k)
+		if err == nil && !verified[kid] && e.signedBy(k, kid, digest[:]) {
+			verified[kid] = true
+		}
+	}
+	if len(verified) < threshold {
+		return nil, fmt.Errorf("%w: %d of %d", ErrEnvelopeThreshold, len(verified), threshold)
+	}
+
+	return e.Payload, nil
+}
+
+// signedBy reports whether one of the signatures belongs to key, signatures naming another key ID are skipped
+func (e *Envelope) signedBy(key *rsa.PublicKey, kid string, digest []byte) bool {
+	for _, s := range e.Signatures {
+		if s.KeyID != "" && s.KeyID != kid {
+			continue
+		}
+		if rsa.VerifyPSS(key, crypto.SHA256, digest, s.Sig, nil) == nil {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,122 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestPAE(t *testing.T) {
+	// DSSE protocol specification
+	tests := []struct {
+		payloadType string
+		payload     string
+		want        string
+	}{
+		{"http://example.com/HelloWorld", "hello world", "DSSEv1 29 http://example.com/HelloWorld 11 hello world"},
+		{"", "", "DSSEv1 0  0 "},
+		{"a b", "c 1 d", "DSSEv1 3 a b 5 c 1 d"},
+	}
+	for _, tt := range tests {
+		if got := string(PAE(tt.payloadType, []byte(tt.payload))); got != tt.want {
+			t.Fatalf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestEnvelopeThreshold(t *testing.T) {
+	keys := make([]*rsa.PrivateKey, 3)
+	pubs := make([]*rsa.PublicKey, len(keys))
+	for i := range keys {
+		key, err := GetPrivateKey(Bits2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i], pubs[i] = key, &key.PublicKey
+	}
+
+	e, err := SignEnvelope("application/vnd.in-toto+json", []byte(`{"_type":"statement"}`), keys[0], keys[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the envelope survives its JSON form
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed Envelope
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		threshold int
+		keys      []*rsa.PublicKey
+		ok        bool
+	}{
+		{"one of all", 1, pubs, true},
+		{"zero means one", 0, pubs[1:], true},
+		{"two of all", 2, pubs, true},
+		{"three of all", 3, pubs, false},
+		{"unsigned key", 1, pubs[2:], false},
+		{"duplicate key", 2, []*rsa.PublicKey{pubs[0], pubs[0], pubs[2]}, false},
+		{"no keys", 1, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := parsed.Verify(tt.threshold, tt.keys...)
+			if tt.ok {
+				if err != nil || string(payload) != string(e.Payload) {
+					t.Fatalf("got %q, %v", payload, err)
+				}
+			} else if !errors.Is(err, ErrEnvelopeThreshold) {
+				t.Fatalf("expected ErrEnvelopeThreshold, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEnvelopeTampering(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(e *Envelope)
+	}{
+		{"payload", func(e *Envelope) { e.Payload = []byte("world") }},
+		{"payload type", func(e *Envelope) { e.PayloadType = "text/html" }},
+		{"signature", func(e *Envelope) { e.Signatures[0].Sig[0] ^= 1 }},
+		{"key id", func(e *Envelope) { e.Signatures[0].KeyID = "other" }},
+		{"no signatures", func(e *Envelope) { e.Signatures = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := SignEnvelope("text/plain", []byte("hello"), key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := e.Verify(1, &key.PublicKey); err != nil {
+				t.Fatal(err)
+			}
+			tt.tamper(e)
+			if _, err := e.Verify(1, &key.PublicKey); !errors.Is(err, ErrEnvelopeThreshold) {
+				t.Fatalf("expected ErrEnvelopeThreshold, got %v", err)
+			}
+		})
+	}
+
+	// a signature without key ID is tried against every key
+	e, err := SignEnvelope("text/plain", []byte("hello"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Signatures[0].KeyID = ""
+	if _, err := e.Verify(1, &key.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,146 @@
+package rsakys
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	hybridVersion byte = 1
+	dataKeySize        = 32
+)
+
+var (
+	// ErrMalformed is returned when a ciphertext does not follow the expected format
+	ErrMalformed = errors.New("ciphertext is malformed")
+	// ErrWrongKey is returned when a ciphertext is not encrypted for the given private key
+	ErrWrongKey = errors.New("ciphertext is not encrypted for this key")
+	// ErrTruncated is returned when a ciphertext ends before its final part
+	ErrTruncated = errors.New("ciphertext is truncated")
+	// ErrTampered is returned when a ciphertext fails to authenticate
+	ErrTampered = errors.New("ciphertext failed to authenticate")
+)
+
+// ChunkError reports the chunk of a stream that failed to authenticate,
+// either because it was modified, reordered, or cut off inside the chunk
+type ChunkError struct {
+	Index uint32
+}
+
+// Error implements the error interface
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("ciphertext chunk %d failed to authenticate", e.Index)
+}
+
+// Unwrap allows errors.Is(err, ErrTampered)
+func (e *ChunkError) Unwrap() error {
+	return ErrTampered
+}
+
+// EncryptOAEP encrypts a short message, at most the key size minus twice the hash size minus 2 bytes,
+// for a given RSA public key with RSA-OAEP, using SHA-256 and no label unless configured otherwise
+func EncryptOAEP(publicKey *rsa.PublicKey, msg []byte, opts ...EncryptOption) ([]byte, error) {
+	o, err := newEncryptOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsa.EncryptOAEP(o.hash.New(), currentConfig().Rand, publicKey, msg, o.label)
+}
+
+// DecryptOAEP decrypts a message encrypted with RSA-OAEP using the same options as EncryptOAEP
+func DecryptOAEP(privateKey *rsa.PrivateKey, ciphertext []byte, opts ...EncryptOption) ([]byte, error) {
+	o, err := newEncryptOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := rsa.DecryptOAEP(o.hash.New(), currentConfig().Rand, privateKey, ciphertext, o.label)
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+
+	return msg, nil
+}
+
+// Encrypt encrypts plaintext of arbitrary length for a given RSA public key.
+// A random AES-256-GCM key seals the plaintext and is itself encrypted with RSA-OAEP,
+// using SHA-256 and no label unless configured otherwise.
+func Encrypt(publicKey *rsa.PublicKey, plaintext []byte, opts ...EncryptOption) ([]byte, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(currentConfig().Rand, dataKey); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := EncryptOAEP(publicKey, dataKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(currentConfig().Rand, nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 3, 3+len(wrapped)+len(nonce)+len(plaintext)+aead.Overhead())
+	out[0] = hybridVersion
+	binary.BigEndian.PutUint16(out[1:], uint16(len(wrapped)))
+	out = append(out, wrapped...)
+	out = append(out, nonce...)
+
+	return aead.Seal(out, nonce, plaintext, out[:3+len(wrapped)]), nil
+}
+
+// Decrypt decrypts a ciphertext produced by Encrypt with the given RSA private key and the same options.
+// Failures are reported as ErrMalformed, ErrWrongKey, ErrTruncated, or ErrTampered.
+func Decrypt(privateKey *rsa.PrivateKey, ciphertext []byte, opts ...EncryptOption) ([]byte, error) {
+	if len(ciphertext) < 3 {
+		return nil, ErrTruncated
+	}
+	if ciphertext[0] != hybridVersion {
+		return nil, ErrMalformed
+	}
+	wrappedLen := int(binary.BigEndian.Uint16(ciphertext[1:]))
+	if len(ciphertext) < 3+wrappedLen {
+		return nil, ErrTruncated
+	}
+	header, rest := ciphertext[:3+wrappedLen], ciphertext[3+wrappedLen:]
+
+	dataKey, err := DecryptOAEP(privateKey, header[3:], opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrTruncated
+	}
+
+	plaintext, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrTampered
+	}
+
+	return plaintext, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestEncryptRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, size := range []int{0, 1, 1 << 16} {
+		plaintext := bytes.Repeat([]byte{0xa5}, size)
+		ciphertext, err := Encrypt(&key.PublicKey, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := Decrypt(key, ciphertext)
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatalf("size %d: plaintext differs", size)
+		}
+	}
+
+	opts := []EncryptOption{WithOAEPHash(crypto.SHA512), WithOAEPLabel([]byte("label"))}
+	ciphertext, err := Encrypt(&key.PublicKey, []byte("msg"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := Decrypt(key, ciphertext, opts...); err != nil || string(got) != "msg" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if _, err := Decrypt(key, ciphertext); !errors.Is(err, ErrWrongKey) {
+		t.Fatalf("expected ErrWrongKey for other options, got %v", err)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := Encrypt(&key.PublicKey, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := bytes.Clone(ciphertext)
+	version[0]++
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name       string
+		key        *rsa.PrivateKey
+		ciphertext []byte
+		err        error
+	}{
+		{"empty", key, nil, ErrTruncated},
+		{"version", key, version, ErrMalformed},
+		{"wrapped key cut off", key, ciphertext[:100], ErrTruncated},
+		{"body cut off", key, ciphertext[:len(ciphertext)-len("secret")-17], ErrTruncated},
+		{"other key", other, ciphertext, ErrWrongKey},
+		{"tampered", key, tampered, ErrTampered},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decrypt(tt.key, tt.ciphertext); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/subtle"
+	"crypto/x509"
+)
+
+// Equal reports whether two private keys are the same,
+// nil keys and keys without an Equal method are never equal
+func Equal(a, b crypto.PrivateKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PrivateKey) bool })
+	if !ok || isNilKey(a) || isNilKey(b) {
+		return false
+	}
+
+	return k.Equal(b)
+}
+
+// EqualPublic reports whether two public keys are the same,
+// nil keys and keys without an Equal method are never equal
+func EqualPublic(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || isNilKey(a) || isNilKey(b) {
+		return false
+	}
+
+	return k.Equal(b)
+}
+
+// ConstantTimeEqualPublic compares the PKIX encodings of two public keys in constant time,
+// only the length of the encodings may leak through timing
+func ConstantTimeEqualPublic(a, b crypto.PublicKey) bool {
+	derA, err := x509.MarshalPKIXPublicKey(a)
+	if err != nil {
+		return false
+	}
+	derB, err := x509.MarshalPKIXPublicKey(b)
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(derA, derB) == 1
+}
+
+// isNilKey also catches typed nil pointers and RSA keys without modulus, on which Equal panics
+func isNilKey(key any) bool {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k == nil || k.N == nil
+	case *rsa.PublicKey:
+		return k == nil || k.N == nil
+	case *ecdsa.PrivateKey:
+		return k == nil
+	case *ecdsa.PublicKey:
+		return k == nil
+	default:
+		return key == nil
+	}
+}
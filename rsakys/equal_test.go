@@ -0,0 +1,96 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"path/filepath"
+	"testing"
+)
+
+func TestEqualNilKeys(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		a, b crypto.PrivateKey
+		want bool
+	}{
+		{"same", key, key, true},
+		{"nil", key, nil, false},
+		{"typed nil", key, (*rsa.PrivateKey)(nil), false},
+		{"typed nil receiver", (*rsa.PrivateKey)(nil), key, false},
+		{"no modulus", &rsa.PrivateKey{}, key, false},
+		{"ecdsa typed nil", (*ecdsa.PrivateKey)(nil), key, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.a, tt.b); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if EqualPublic((*rsa.PublicKey)(nil), &key.PublicKey) || !EqualPublic(&key.PublicKey, &key.PublicKey) {
+		t.Fatal("EqualPublic mishandles nil keys")
+	}
+	if !ConstantTimeEqualPublic(&key.PublicKey, &key.PublicKey) {
+		t.Fatal("ConstantTimeEqualPublic rejects the same key")
+	}
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	private := []struct {
+		name  string
+		write func(*rsa.PrivateKey, string, ...WriteOption) error
+	}{
+		{"PKCS1", WritePKCS1PrivateKey},
+		{"PKCS8", WritePKCS8PrivateKey},
+	}
+	for _, tt := range private {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".pem")
+			if err := tt.write(key, path); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadPrivate(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !Equal(read, key) {
+				t.Fatal("private key did not round-trip")
+			}
+		})
+	}
+
+	public := []struct {
+		name  string
+		write func(*rsa.PublicKey, string, ...WriteOption) error
+	}{
+		{"PKCS1 public", WritePKCS1PublicKey},
+		{"PKIX", WritePKIXPublicKey},
+	}
+	for _, tt := range public {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".pub")
+			if err := tt.write(&key.PublicKey, path); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadPublic(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !EqualPublic(read, &key.PublicKey) || !ConstantTimeEqualPublic(read, &key.PublicKey) {
+				t.Fatal("public key did not round-trip")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+var (
+	errNoCustodianShare = errors.New("bundle holds no share for this custodian")
+	errEscrowKey        = errors.New("recovered key does not match the escrowed fingerprint")
+)
+
+// EscrowBundle holds the shares of a split private key,
+// each encrypted for the RSA public key of a different custodian
+type EscrowBundle struct {
+	Threshold   int           `json:"threshold"`
+	Fingerprint string        `json:"fingerprint"`
+	Shares      []EscrowShare `json:"shares"`
+}
+
+// EscrowShare is a share encrypted for a single custodian,
+// identified by the fingerprint of the custodian's public key
+type EscrowShare struct {
+	Custodian  string `json:"custodian"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EscrowKey splits a given RSA private key into one share per custodian,
+// of which any k reconstruct the key, and encrypts every share for its custodian
+func EscrowKey(privateKey *rsa.PrivateKey, custodians []*rsa.PublicKey, k int) (*EscrowBundle, error) {
+	fp, err := Fingerprint(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	shares, err := SplitKey(privateKey, len(custodians), k)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &EscrowBundle{
+		Threshold:   k,
+		Fingerprint: fp,
+		Shares:      make([]EscrowShare, len(custodians)),
+	}
+	for i, custodian := range custodians {
+		cfp, err := Fingerprint(custodian)
+		if err != nil {
+			return nil, err
+		}
+		ct, err := Encrypt(custodian, []byte(shares[i].String()))
+		if err != nil {
+			return nil, err
+		}
+		bundle.Shares[i] = EscrowShare{
+			Custodian:  cfp,
+			Ciphertext: ct,
+		}
+	}
+
+	return bundle, nil
+}
+
+// DecryptShare decrypts the share belonging to the custodian of the given RSA private key
+func (b *EscrowBundle) DecryptShare(custodian *rsa.PrivateKey) (Share, error) {
+	fp, err := Fingerprint(&custodian.PublicKey)
+	if err != nil {
+		return Share{}, err
+	}
+
+	for _, s := range b.Shares {
+		if s.Custodian != fp {
+			continue
+		}
+		pt, err := Decrypt(custodian, s.Ciphertext)
+		if err != nil {
+			return Share{}, err
+		}
+
+		return ParseShare(string(pt))
+	}
+
+	return Share{}, errNoCustodianShare
+}
+
+// Recover reconstructs the escrowed RSA private key from the decrypted shares of at least threshold custodians
+func (b *EscrowBundle) Recover(shares []Share) (*rsa.PrivateKey, error) {
+	privateKey, err := CombineShares(shares)
+	if err != nil {
+		return nil, err
+	}
+
+	fp, err := Fingerprint(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if fp != b.Fingerprint {
+		return nil, errEscrowKey
+	}
+
+	return privateKey, nil
+}
+
+// WriteEscrowBundle atomically writes an escrow bundle as JSON file to disc with the private key file mode
+func WriteEscrowBundle(path string, bundle *EscrowBundle) error {
+	cntnt, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(path, currentConfig().PrivatePerm, func(w io.Writer) error {
+		_, err := w.Write(append(cntnt, '\n'))
+		return err
+	})
+}
+
+// ReadEscrowBundle reads an escrow bundle JSON file
+func ReadEscrowBundle(path string) (*EscrowBundle, error) {
+	f, err := os.Open(fixPath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bundle := &EscrowBundle{}
+	if err := json.NewDecoder(f).Decode(bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
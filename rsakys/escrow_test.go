@@ -0,0 +1,81 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestEscrowRecover(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	custodians := make([]*rsa.PrivateKey, 3)
+	publicKeys := make([]*rsa.PublicKey, len(custodians))
+	for i := range custodians {
+		if custodians[i], err = GetPrivateKey(Bits2048); err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = &custodians[i].PublicKey
+	}
+
+	bundle, err := EscrowKey(key, publicKeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "escrow.json")
+	if err := WriteEscrowBundle(path, bundle); err != nil {
+		t.Fatal(err)
+	}
+	if bundle, err = ReadEscrowBundle(path); err != nil {
+		t.Fatal(err)
+	}
+
+	shares := make([]Share, len(custodians))
+	for i, c := range custodians {
+		if shares[i], err = bundle.DecryptShare(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		shares []Share
+		err    error
+	}{
+		{"first two", shares[:2], nil},
+		{"last two", shares[1:], nil},
+		{"outer two", []Share{shares[2], shares[0]}, nil},
+		{"all", shares, nil},
+		{"one", shares[:1], errShareCount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recovered, err := bundle.Recover(tt.shares)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if err == nil && !Equal(recovered, key) {
+				t.Fatal("recovered key differs")
+			}
+		})
+	}
+
+	outsider, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bundle.DecryptShare(outsider); !errors.Is(err, errNoCustodianShare) {
+		t.Fatalf("expected errNoCustodianShare, got %v", err)
+	}
+
+	otherBundle, err := EscrowKey(outsider, publicKeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := otherBundle.Recover(shares[:2]); !errors.Is(err, errEscrowKey) {
+		t.Fatalf("expected errEscrowKey, got %v", err)
+	}
+}
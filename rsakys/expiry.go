@@ -0,0 +1,136 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// ExpiryPolicy decides how a key past its not-after date is handled.
+// LoadPrivate and LoadPublic enforce it on load, the other read functions ignore it.
+// A private key from LoadPrivate remembers its not-after date and policy, so the signing helpers,
+// e.g. Envelope.Sign, Delegate, BlindSign, CreateManifest, threshold shares, and PolicyKey,
+// enforce it again when the key expires while in use.
+type ExpiryPolicy uint
+
+const (
+	// ExpiryIgnore loads expired keys without further notice
+	ExpiryIgnore ExpiryPolicy = iota
+	// ExpiryWarn loads expired keys and logs a warning, strict configurations refuse them
+	ExpiryWarn
+	// ExpiryRefuse rejects expired keys with ErrKeyExpired
+	ExpiryRefuse
+)
+
+// ErrKeyExpired is returned when a key is past the not-after date of its policy
+var ErrKeyExpired = errors.New("key is past its not-after date")
+
+// signingExpiry holds the *keyExpiry of private keys from LoadPrivate, keyed by modulus
+var signingExpiry sync.Map
+
+type keyExpiry struct {
+	path     string
+	notAfter time.Time
+	policy   ExpiryPolicy
+	// warned logs the warning of ExpiryWarn once instead of on every signature
+	warned sync.Once
+}
+
+// Expired reports whether the not-after date of the metadata lies before t
+func (md *Metadata) Expired(t time.Time) bool {
+	return md.NotAfter != nil && t.After(*md.NotAfter)
+}
+
+// SetNotAfter attaches a not-after date to the metadata sidecar of a key file
+func SetNotAfter(keyPath string, notAfter time.Time) error {
+	return UpdateMetadata(keyPath, func(md *Metadata) {
+		t := notAfter.UTC()
+		md.NotAfter = &t
+	})
+}
+
+// LoadPrivate reads a private key PEM file and enforces the not-after date
+// of its metadata sidecar according to the given policy.
+// Keys without a sidecar are not subject to the policy.
+func LoadPrivate(path string, policy ExpiryPolicy) (*rsa.PrivateKey, error) {
+	expiry, err := checkExpiry(path, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := readPrivate(path)
+	if err != nil {
+		return nil, err
+	}
+	if expiry != nil {
+		signingExpiry.Store(string(key.N.Bytes()), expiry)
+	}
+
+	return key, nil
+}
+
+// LoadPublic reads a public key PEM file and enforces the not-after date
+// of its metadata sidecar according to the given policy.
+// Keys without a sidecar are not subject to the policy.
+func LoadPublic(path string, policy ExpiryPolicy) (*rsa.PublicKey, error) {
+	if _, err := checkExpiry(path, policy); err != nil {
+		return nil, err
+	}
+
+	return readPublic(path)
+}
+
+func checkExpiry(path string, policy ExpiryPolicy) (*keyExpiry, error) {
+	if policy == ExpiryIgnore {
+		return nil, nil
+	}
+
+	md, err := ReadMetadata(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if md.NotAfter == nil {
+		return nil, nil
+	}
+
+	expiry := &keyExpiry{path: path, notAfter: *md.NotAfter, policy: policy}
+
+	return expiry, expiry.check()
+}
+
+// checkSigningKey enforces the policy a private key with modulus n was loaded with by LoadPrivate,
+// keys read otherwise are not subject to it
+func checkSigningKey(n *big.Int) error {
+	if n == nil {
+		return nil
+	}
+	expiry, ok := signingExpiry.Load(string(n.Bytes()))
+	if !ok {
+		return nil
+	}
+
+	return expiry.(*keyExpiry).check()
+}
+
+func (e *keyExpiry) check() error {
+	if !time.Now().After(e.notAfter) {
+		return nil
+	}
+
+	notAfter := e.notAfter.Format(time.RFC3339)
+	if e.policy == ExpiryRefuse || currentConfig().Strict {
+		return fmt.Errorf("%s: %w (%s)", e.path, ErrKeyExpired, notAfter)
+	}
+	e.warned.Do(func() {
+		currentConfig().Logger.Printf("rsakys: key %s is past its not-after date %s, consider rotating it", e.path, notAfter)
+	})
+
+	return nil
+}
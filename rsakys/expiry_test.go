@@ -0,0 +1,135 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// logRecorder collects the warnings of the package
+type logRecorder struct {
+	lines []string
+}
+
+func (l *logRecorder) Printf(format string, v ...any) {
+	l.lines = append(l.lines, format)
+}
+
+// loadExpiringKey writes a key with a not-after date an hour from now, loads it with policy,
+// and then moves the remembered not-after date into the past
+func loadExpiringKey(t *testing.T, policy ExpiryPolicy) *PolicyKey {
+	t.Helper()
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "k.pem")
+	if err := WritePKCS8PrivateKey(key, path, WithMetadata("sign")); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetNotAfter(path, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadPrivate(path, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiry, ok := signingExpiry.Load(string(loaded.N.Bytes()))
+	if !ok {
+		t.Fatal("LoadPrivate did not remember the not-after date")
+	}
+	expiry.(*keyExpiry).notAfter = time.Now().Add(-time.Minute)
+	t.Cleanup(func() { signingExpiry.Delete(string(loaded.N.Bytes())) })
+
+	return NewPolicyKey(loaded, UsageSign|UsageCertSign)
+}
+
+func expiredSigners(t *testing.T, k *PolicyKey) map[string]func() error {
+	key := k.key
+	digest := sha256.Sum256([]byte("msg"))
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ca"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	return map[string]func() error{
+		"dsse": func() error {
+			_, err := SignEnvelope("text/plain", []byte("msg"), key)
+			return err
+		},
+		"delegate": func() error {
+			_, err := Delegate(key, &key.PublicKey, []string{"a"}, time.Hour)
+			return err
+		},
+		"delegated signature": func() error {
+			_, err := SignDelegated(key, nil, []byte("msg"))
+			return err
+		},
+		"blind": func() error {
+			_, err := BlindSign(key, make([]byte, key.Size()))
+			return err
+		},
+		"manifest": func() error {
+			_, err := CreateManifest(t.TempDir(), key)
+			return err
+		},
+		"threshold share": func() error {
+			share := &ThresholdShare{Index: 1, Threshold: 1, Parties: 1, PublicKey: &key.PublicKey, Secret: big.NewInt(1)}
+			_, err := share.Sign(crypto.SHA256, digest[:])
+			return err
+		},
+		"policy key": func() error {
+			_, err := k.Sign(nil, digest[:], crypto.SHA256)
+			return err
+		},
+		"certificate": func() error {
+			_, err := k.CreateCertificate(template, template, &key.PublicKey)
+			return err
+		},
+	}
+}
+
+func TestSigningRefusesExpiredKey(t *testing.T) {
+	k := loadExpiringKey(t, ExpiryRefuse)
+	for name, sign := range expiredSigners(t, k) {
+		t.Run(name, func(t *testing.T) {
+			if err := sign(); !errors.Is(err, ErrKeyExpired) {
+				t.Fatalf("expected ErrKeyExpired, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSigningWarnsOnceForExpiredKey(t *testing.T) {
+	logs := &logRecorder{}
+	withConfig(t, Config{Logger: logs})
+
+	k := loadExpiringKey(t, ExpiryWarn)
+	for name, sign := range expiredSigners(t, k) {
+		if err := sign(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+	if len(logs.lines) != 1 {
+		t.Fatalf("expected a single warning, got %d", len(logs.lines))
+	}
+}
+
+func TestSigningIgnoresKeysNotLoadedWithPolicy(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSigningKey(key.N); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+package rsakys
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+var oidRSAEncryption = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+
+// readEncoded returns the PEM block of the file at path re-encoded without headers, if it is of
+// blockType and its DER already has the requested encoding. It checks the ASN.1 structure and the
+// key's arithmetic like rsa.PrivateKey.Validate, but skips the costly precomputation and consistency
+// checks of a full parse. It is bypassed in strict mode where keys must be linted, keys failing the
+// checks fall back to the full parse and its error.
+func readEncoded(path, blockType string, isEncoded func(der []byte) bool) ([]byte, bool) {
+	if currentConfig().Strict {
+		return nil, false
+	}
+
+	cntnt, err := readFile(path)
+	if err != nil {
+		return nil, false
+	}
+	block, _ := pem.Decode(cntnt)
+	if block == nil || block.Type != blockType || !isEncoded(block.Bytes) {
+		return nil, false
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: block.Bytes}), true
+}
+
+// isPKCS1PrivateKey checks for SEQUENCE { version, n, e, d, p, q, dp, dq, qinv } of a valid two-prime key
+func isPKCS1PrivateKey(der []byte) bool {
+	input := cryptobyte.String(der)
+	var seq cryptobyte.String
+	if !input.ReadASN1(&seq, cbasn1.SEQUENCE) || !input.Empty() {
+		return false
+	}
+
+	var version int
+	if !seq.ReadASN1Integer(&version) || version != 0 {
+		return false
+	}
+
+	ints, ok := readIntegers(&seq, 8)
+
+	return ok && seq.Empty() && validPrivate(ints[0], ints[1], ints[2], ints[3], ints[4], ints[5], ints[6], ints[7])
+}
+
+// isPKCS1PublicKey checks for SEQUENCE { n, e } with an odd modulus and an exponent accepted by crypto/rsa
+func isPKCS1PublicKey(der []byte) bool {
+	input := cryptobyte.String(der)
+	var seq cryptobyte.String
+	if !input.ReadASN1(&seq, cbasn1.SEQUENCE) || !input.Empty() {
+		return false
+	}
+
+	ints, ok := readIntegers(&seq, 2)
+
+	return ok && seq.Empty() && validPublic(ints[0], ints[1])
+}
+
+func validPublic(n, e *big.Int) bool {
+	return n.Bit(0) == 1 && e.Bit(0) == 1 && e.Cmp(big.NewInt(2)) > 0 && e.BitLen() <= 31
+}
+
+// validPrivate checks n = pq, de ≡ 1 mod p-1 and q-1 as rsa.PrivateKey.Validate does,
+// and the CRT values, which a full parse would otherwise recompute
+func validPrivate(n, e, d, p, q, dp, dq, qinv *big.Int) bool {
+	if !validPublic(n, e) || new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		return false
+	}
+
+	one := big.NewInt(1)
+	for _, pair := range [][2]*big.Int{{p, dp}, {q, dq}} {
+		pMinus1 := new(big.Int).Sub(pair[0], one)
+		if pMinus1.Sign() <= 0 {
+			return false
+		}
+		de := new(big.Int).Mul(d, e)
+		if de.Mod(de, pMinus1).Cmp(one) != 0 || new(big.Int).Mod(d, pMinus1).Cmp(pair[1]) != 0 {
+			return false
+		}
+	}
+	qq := new(big.Int).Mul(qinv, q)
+
+	return qinv.Cmp(p) < 0 && qq.Mod(qq, p).Cmp(one) == 0
+}
+
+// isPKCS8PrivateKey checks for SEQUENCE { version, AlgorithmIdentifier { rsaEncryption }, OCTET STRING { PKCS1 } }
+func isPKCS8PrivateKey(der []byte) bool {
+	input := cryptobyte.String(der)
+	var seq, inner cryptobyte.String
+	var version int
+	if !input.ReadASN1(&seq, cbasn1.SEQUENCE) || !input.Empty() || !seq.ReadASN1Integer(&version) || version != 0 {
+		return false
+	}
+	if !readRSAAlgorithm(&seq) || !seq.ReadASN1(&inner, cbasn1.OCTET_STRING) {
+		return false
+	}
+
+	return seq.Empty() && isPKCS1PrivateKey(inner)
+}
+
+// isPKIXPublicKey checks for SEQUENCE { AlgorithmIdentifier { rsaEncryption }, BIT STRING { PKCS1 } }
+func isPKIXPublicKey(der []byte) bool {
+	input := cryptobyte.String(der)
+	var seq cryptobyte.String
+	var key asn1.BitString
+	if !input.ReadASN1(&seq, cbasn1.SEQUENCE) || !input.Empty() || !readRSAAlgorithm(&seq) {
+		return false
+	}
+	if !seq.ReadASN1BitString(&key) || !seq.Empty() || key.BitLength%8 != 0 {
+		return false
+	}
+
+	return isPKCS1PublicKey(key.Bytes)
+}
+
+func readRSAAlgorithm(s *cryptobyte.String) bool {
+	var algo cryptobyte.String
+	var oid asn1.ObjectIdentifier
+
+	return s.ReadASN1(&algo, cbasn1.SEQUENCE) && algo.ReadASN1ObjectIdentifier(&oid) && oid.Equal(oidRSAEncryption)
+}
+
+// readIntegers reads n positive INTEGERs
+func readIntegers(s *cryptobyte.String, n int) ([]*big.Int, bool) {
+	ints := make([]*big.Int, n)
+	for i := range ints {
+		ints[i] = new(big.Int)
+		if !s.ReadASN1Integer(ints[i]) || ints[i].Sign() <= 0 {
+			return nil, false
+		}
+	}
+
+	return ints, true
+}
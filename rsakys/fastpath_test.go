@@ -0,0 +1,126 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type pkcs1PrivateKey struct {
+	Version               int
+	N                     *big.Int
+	E                     int
+	D, P, Q, Dp, Dq, Qinv *big.Int
+}
+
+func writeTestKey(tb testing.TB, format Format) (string, *rsa.PrivateKey) {
+	tb.Helper()
+
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	path := filepath.Join(tb.TempDir(), "key.pem")
+	if err := writePrivateKey(path, key, format); err != nil {
+		tb.Fatal(err)
+	}
+
+	return path, key
+}
+
+func TestReadPrivatePKCS1RejectsInconsistentKey(t *testing.T) {
+	path, key := writeTestKey(t, PKCS1)
+
+	// a key of the right shape whose modulus is not p*q
+	der, err := asn1.Marshal(pkcs1PrivateKey{
+		N: new(big.Int).Add(key.N, big.NewInt(2)), E: key.E, D: key.D, P: key.Primes[0], Q: key.Primes[1],
+		Dp: key.Precomputed.Dp, Dq: key.Precomputed.Dq, Qinv: key.Precomputed.Qinv,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: privateType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadPrivatePKCS1(path); err == nil {
+		t.Fatal("inconsistent key was returned without error")
+	}
+}
+
+func BenchmarkReadPrivatePKCS1(b *testing.B) {
+	path, _ := writeTestKey(b, PKCS1)
+
+	b.Run("fast", func(b *testing.B) {
+		for range b.N {
+			if _, err := ReadPrivatePKCS1(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		for range b.N {
+			key, err := readPrivate(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := encodePrivateKey(key, PKCS1); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkReadPrivatePKCS8(b *testing.B) {
+	path, _ := writeTestKey(b, PKCS8)
+
+	b.Run("fast", func(b *testing.B) {
+		for range b.N {
+			if _, err := ReadPrivatePKCS8(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		for range b.N {
+			key, err := readPrivate(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := encodePrivateKey(key, PKCS8); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkReadPublicPKIX(b *testing.B) {
+	_, key := writeTestKey(b, PKCS1)
+	path := filepath.Join(b.TempDir(), "key.pub")
+	if err := writePublicKey(path, &key.PublicKey, PKIX); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("fast", func(b *testing.B) {
+		for range b.N {
+			if _, err := ReadPublicPKIX(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("full", func(b *testing.B) {
+		for range b.N {
+			pub, err := readPublic(path)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := encodePublicKey(pub, PKIX); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
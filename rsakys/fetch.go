@@ -0,0 +1,146 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultFetchTimeout = 10 * time.Second
+	maxFetchRedirects   = 10
+)
+
+var (
+	errNotHTTPS = errors.New("public keys can only be fetched over https")
+	errNoPin    = errors.New("either an SPKI pin or a CA allowlist is required")
+	errPin      = errors.New("server certificate does not match any SPKI pin")
+	errRedirect = errors.New("too many redirects fetching public key")
+)
+
+// PinOptions configures how FetchPublicKey authenticates the remote server
+type PinOptions struct {
+	// SPKIPins are base64 encoded SHA-256 digests of accepted SubjectPublicKeyInfos,
+	// a pin matches if any certificate of the verified chain carries that key
+	SPKIPins []string
+	// RootCAs is the allowlist of certificate authorities, the system pool is used if nil
+	RootCAs *x509.CertPool
+	// Timeout limits the whole request, defaults to 10 seconds
+	Timeout time.Duration
+}
+
+// FetchPublicKey downloads a PEM or JWK encoded public key over HTTPS,
+// enforces the given SPKI pins or CA allowlist, and returns the public key struct
+func FetchPublicKey(rawURL string, opts PinOptions) (*rsa.PublicKey, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" {
+		return nil, errNotHTTPS
+	}
+	if len(opts.SPKIPins) == 0 && opts.RootCAs == nil {
+		return nil, errNoPin
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+
+	verify := verifySPKIPins(opts.SPKIPins)
+	client := &http.Client{
+		Timeout:       timeout,
+		CheckRedirect: checkRedirect(verify),
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{
+				MinVersion:       tls.VersionTLS12,
+				RootCAs:          opts.RootCAs,
+				VerifyConnection: verify,
+			},
+		},
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := verifyResponse(resp, verify); err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching public key: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, tenKB))
+	if err != nil {
+		return nil, err
+	}
+
+	if body = bytes.TrimSpace(body); bytes.HasPrefix(body, []byte("{")) {
+		return parseJWK(body)
+	}
+
+	return parsePublic(body)
+}
+
+// checkRedirect refuses to leave https and re-checks the pins of the hop that redirected,
+// a reused connection is otherwise never verified again
+func checkRedirect(verify func(tls.ConnectionState) error) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxFetchRedirects {
+			return errRedirect
+		}
+		if req.URL.Scheme != "https" {
+			return fmt.Errorf("%w: redirect to %s", errNotHTTPS, req.URL.Redacted())
+		}
+
+		return verifyResponse(req.Response, verify)
+	}
+}
+
+// verifyResponse ensures a response was received over TLS and matches the pins
+func verifyResponse(resp *http.Response, verify func(tls.ConnectionState) error) error {
+	if resp == nil || resp.TLS == nil {
+		return errNotHTTPS
+	}
+	if verify == nil {
+		return nil
+	}
+
+	return verify(*resp.TLS)
+}
+
+func verifySPKIPins(pins []string) func(tls.ConnectionState) error {
+	if len(pins) == 0 {
+		return nil
+	}
+
+	return func(cs tls.ConnectionState) error {
+		for _, chain := range cs.VerifiedChains {
+			for _, cert := range chain {
+				sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				fp := base64.StdEncoding.EncodeToString(sum[:])
+				for _, pin := range pins {
+					if pin == fp {
+						return nil
+					}
+				}
+			}
+		}
+
+		return errPin
+	}
+}
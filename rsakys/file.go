@@ -0,0 +1,111 @@
+package rsakys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// EncryptFile encrypts the file at inPath for the public key PEM file at pubKeyPath
+// and atomically writes the result to outPath, preserving the file mode of inPath
+func EncryptFile(pubKeyPath, inPath, outPath string, opts ...EncryptOption) error {
+	publicKey, err := readPublic(pubKeyPath)
+	if err != nil {
+		return err
+	}
+
+	return transformFile(inPath, outPath, func(dst io.Writer, src io.Reader) error {
+		return EncryptStreamWithOptions(dst, src, []*rsa.PublicKey{publicKey}, opts...)
+	})
+}
+
+// DecryptFile decrypts the file at inPath with the private key PEM file at privKeyPath
+// and atomically writes the plaintext to outPath, preserving the file mode of inPath.
+// outPath is only created if the whole file authenticated successfully.
+func DecryptFile(privKeyPath, inPath, outPath string, opts ...EncryptOption) error {
+	privateKey, err := readPrivate(privKeyPath)
+	if err != nil {
+		return err
+	}
+
+	return transformFile(inPath, outPath, func(dst io.Writer, src io.Reader) error {
+		return DecryptStream(dst, src, privateKey, opts...)
+	})
+}
+
+// VerifyFile checks the integrity of the encrypted file at inPath with the private key PEM file
+// at privKeyPath without writing any plaintext, reporting failures like DecryptStream
+func VerifyFile(privKeyPath, inPath string, opts ...EncryptOption) error {
+	privateKey, err := readPrivate(privKeyPath)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(fixPath(inPath))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	return VerifyStream(in, privateKey, opts...)
+}
+
+func transformFile(inPath, outPath string, fn func(dst io.Writer, src io.Reader) error) error {
+	in, err := os.Open(fixPath(inPath))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(outPath, info.Mode().Perm(), func(w io.Writer) error {
+		return fn(w, in)
+	})
+}
+
+// writeAtomic writes to a temporary file next to path and renames it into place once fn succeeded
+func writeAtomic(path string, perm os.FileMode, fn func(w io.Writer) error) (err error) {
+	path = fixPath(path)
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = fn(tmp); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// randomSuffix returns a random hex string for temporary file names and lock tokens
+func randomSuffix() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,122 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptFileRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	privPath, pubPath := filepath.Join(dir, "k.pem"), filepath.Join(dir, "k.pub")
+	if err := WritePKCS8PrivateKey(key, privPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := WritePKIXPublicKey(&key.PublicKey, pubPath); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := bytes.Repeat([]byte("data"), streamChunkSize)
+	in, enc, out := filepath.Join(dir, "in"), filepath.Join(dir, "in.enc"), filepath.Join(dir, "out")
+	mustWrite(t, in, string(plaintext), 0o640)
+
+	if err := EncryptFile(pubPath, in, enc); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyFile(privPath, enc); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecryptFile(privPath, enc, out); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatal("plaintext differs")
+	}
+
+	// a failed decryption leaves no output behind
+	ciphertext, err := os.ReadFile(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, enc, string(ciphertext[:len(ciphertext)-1]), 0o640)
+	failed := filepath.Join(dir, "failed")
+	if err := DecryptFile(privPath, enc, failed); err == nil {
+		t.Fatal("decrypted a modified file")
+	}
+	if err := VerifyFile(privPath, enc); err == nil {
+		t.Fatal("verified a modified file")
+	}
+	if _, err := os.Stat(failed); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("output of a failed decryption exists: %v", err)
+	}
+}
+
+func TestDecryptStreamIntegrity(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// three chunks, the last one partial
+	var buf bytes.Buffer
+	if err := EncryptStream(&buf, bytes.NewReader(make([]byte, 2*streamChunkSize+10)), &key.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	stream := buf.Bytes()
+	headerSize := len(streamMagic) + streamNoncePrefixSize + 2 + 2 + key.Size()
+	sealedSize := streamChunkSize + 16
+	chunk := func(i int) []byte {
+		return stream[headerSize+i*sealedSize : min(headerSize+(i+1)*sealedSize, len(stream))]
+	}
+	join := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	flipped := bytes.Clone(chunk(1))
+	flipped[5] ^= 1
+
+	tests := []struct {
+		name   string
+		stream []byte
+		key    *rsa.PrivateKey
+		chunk  int
+		err    error
+	}{
+		{"intact", stream, key, -1, nil},
+		{"bad magic", join([]byte("XXXX"), stream[4:]), key, -1, ErrMalformed},
+		{"cut in header", stream[:headerSize-1], key, -1, ErrMalformed},
+		{"no chunks", stream[:headerSize], key, -1, ErrTruncated},
+		{"cut after a chunk", stream[:headerSize+sealedSize], key, -1, ErrTruncated},
+		{"last chunk dropped", stream[:headerSize+2*sealedSize], key, -1, ErrTruncated},
+		{"cut inside a chunk", stream[:headerSize+sealedSize+100], key, 1, ErrTampered},
+		{"modified chunk", join(stream[:headerSize], chunk(0), flipped, chunk(2)), key, 1, ErrTampered},
+		{"reordered chunks", join(stream[:headerSize], chunk(1), chunk(0), chunk(2)), key, 0, ErrTampered},
+		{"other key", stream, other, -1, ErrWrongKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyStream(bytes.NewReader(tt.stream), tt.key)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			var chunkErr *ChunkError
+			if errors.As(err, &chunkErr) != (tt.chunk >= 0) || (tt.chunk >= 0 && chunkErr.Index != uint32(tt.chunk)) {
+				t.Fatalf("expected chunk %d, got %v", tt.chunk, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const cBytesPerLine = 12
+
+var (
+	cIdentifier   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	errIdentifier = errors.New("name is not a valid C identifier")
+)
+
+// ExportCHeader renders the DER encoded public key as C header declaring 'static const uint8_t <name>[]'
+// and '<name>_len', e.g. to embed an update verification key in microcontroller firmware.
+// Format must be PKCS1 or PKIX.
+func ExportCHeader(publicKey *rsa.PublicKey, name string, format Format) ([]byte, error) {
+	if !cIdentifier.MatchString(name) {
+		return nil, fmt.Errorf("%w: %q", errIdentifier, name)
+	}
+
+	der, err := getPublicKeyBlock(publicKey, format)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := Fingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	guard := strings.ToUpper(name) + "_H"
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "/* %s RSA-%d public key, %s */\n", format, publicKey.N.BitLen(), fp)
+	fmt.Fprintf(&buf, "#ifndef %s\n#define %s\n\n#include <stddef.h>\n#include <stdint.h>\n\n", guard, guard)
+	fmt.Fprintf(&buf, "static const uint8_t %s[%d] = {", name, len(der))
+	for i, b := range der {
+		if i%cBytesPerLine == 0 {
+			buf.WriteString("\n   ")
+		}
+		fmt.Fprintf(&buf, " 0x%02x,", b)
+	}
+	fmt.Fprintf(&buf, "\n};\n\nstatic const size_t %s_len = %d;\n\n#endif /* %s */\n", name, len(der), guard)
+
+	return buf.Bytes(), nil
+}
+
+// ExportBlob returns the DER encoded public key prefixed with its length as big-endian uint32,
+// for firmware that reads the key from a raw binary section.
+// Format must be PKCS1 or PKIX.
+func ExportBlob(publicKey *rsa.PublicKey, format Format) ([]byte, error) {
+	der, err := getPublicKeyBlock(publicKey, format)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, 4, 4+len(der))
+	binary.BigEndian.PutUint32(blob, uint32(len(der)))
+
+	return append(blob, der...), nil
+}
@@ -0,0 +1,244 @@
+package rsakys
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrGeneratorClosed is returned for jobs submitted after, or aborted by, Shutdown
+var ErrGeneratorClosed = errors.New("generator is shut down")
+
+// GenerateJob describes a key to generate. If Path is set, the keypair is written to
+// '<Path>/<Keyname>.pem' and '.pub' in the given format, PKCS1 or PKCS8, or Config.PrivateFormat if unset.
+type GenerateJob struct {
+	ID      string
+	Bits    int
+	Format  Format
+	Path    string
+	Keyname string
+	Options []WriteOption
+	// OnProgress is called from the worker whenever the job enters a new stage, completion is reported to onDone
+	OnProgress func(GenerateProgress)
+}
+
+// GenerateStage is the stage a running job entered
+type GenerateStage uint
+
+// Stages of a job, in the order they are reported
+const (
+	// GenerateStarted is reported when a worker picks up the job and starts generating the key
+	GenerateStarted GenerateStage = iota
+	// GenerateWriting is reported when the key is generated and is being written to Path
+	GenerateWriting
+)
+
+// String returns the name of the stage
+func (s GenerateStage) String() string {
+	switch s {
+	case GenerateStarted:
+		return "started"
+	case GenerateWriting:
+		return "writing"
+	default:
+		return fmt.Sprintf("GenerateStage(%d)", uint(s))
+	}
+}
+
+// GenerateProgress reports a job entering a stage, Elapsed is the time since the worker picked it up
+type GenerateProgress struct {
+	Job     GenerateJob
+	Stage   GenerateStage
+	Elapsed time.Duration
+}
+
+// GenerateResult reports the outcome of a job
+type GenerateResult struct {
+	Job      GenerateJob
+	Key      *rsa.PrivateKey
+	Err      error
+	Duration time.Duration
+}
+
+// GeneratorStats is a snapshot of the jobs of a generator, aborted jobs count as failed
+type GeneratorStats struct {
+	Queued    int
+	Running   int
+	Completed int
+	Failed    int
+}
+
+// Generator generates keys on a bounded pool of workers, e.g. for provisioning servers minting many keys
+type Generator struct {
+	jobs   chan GenerateJob
+	done   chan struct{}
+	onDone func(GenerateResult)
+	wg     sync.WaitGroup
+
+	// submits tracks Submit calls in flight, jobs is closed once all of them returned
+	mu        sync.Mutex
+	closed    bool
+	submits   sync.WaitGroup
+	closeJobs sync.Once
+	abort     atomic.Bool
+
+	queued, running, completed, failed atomic.Int64
+}
+
+// NewGenerator starts workers goroutines with room for queue pending jobs.
+// onDone is called from the workers for every finished, failed, or aborted job and must be safe for concurrent use.
+func NewGenerator(workers, queue int, onDone func(GenerateResult)) *Generator {
+	g := &Generator{
+		jobs:   make(chan GenerateJob, max(queue, 0)),
+		done:   make(chan struct{}),
+		onDone: onDone,
+	}
+	if g.onDone == nil {
+		g.onDone = func(GenerateResult) {}
+	}
+
+	g.wg.Add(max(workers, 1))
+	for i := 0; i < max(workers, 1); i++ {
+		go g.work()
+	}
+
+	return g
+}
+
+// Submit queues a job, blocking while the queue is full until ctx is done or the generator is shut down.
+// It may be called from onDone, but a full queue then blocks the calling worker.
+func (g *Generator) Submit(ctx context.Context, job GenerateJob) error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return ErrGeneratorClosed
+	}
+	g.submits.Add(1)
+	g.mu.Unlock()
+	defer g.submits.Done()
+
+	g.queued.Add(1)
+	select {
+	case g.jobs <- job:
+		return nil
+	case <-g.done:
+		g.queued.Add(-1)
+		return ErrGeneratorClosed
+	case <-ctx.Done():
+		g.queued.Add(-1)
+		return ctx.Err()
+	}
+}
+
+// Shutdown stops accepting jobs and waits for the queued ones to finish, blocked Submit calls
+// return ErrGeneratorClosed. If ctx is done first, jobs not yet started are aborted with
+// ErrGeneratorClosed, running generations cannot be interrupted and are still awaited.
+func (g *Generator) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	if !g.closed {
+		g.closed = true
+		close(g.done)
+	}
+	g.mu.Unlock()
+
+	// pending submits return right away as done is closed, afterwards nothing sends on jobs anymore
+	g.closeJobs.Do(func() {
+		g.submits.Wait()
+		close(g.jobs)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.abort.Store(true)
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Stats returns the current number of queued, running, completed, and failed jobs
+func (g *Generator) Stats() GeneratorStats {
+	return GeneratorStats{
+		Queued:    int(g.queued.Load()),
+		Running:   int(g.running.Load()),
+		Completed: int(g.completed.Load()),
+		Failed:    int(g.failed.Load()),
+	}
+}
+
+func (g *Generator) work() {
+	defer g.wg.Done()
+
+	for job := range g.jobs {
+		g.queued.Add(-1)
+		if g.abort.Load() {
+			g.failed.Add(1)
+			g.onDone(GenerateResult{Job: job, Err: ErrGeneratorClosed})
+			continue
+		}
+
+		g.running.Add(1)
+		start := time.Now()
+		key, err := runJob(job, start)
+		g.running.Add(-1)
+
+		if err != nil {
+			g.failed.Add(1)
+		} else {
+			g.completed.Add(1)
+		}
+		g.onDone(GenerateResult{Job: job, Key: key, Err: err, Duration: time.Since(start)})
+	}
+}
+
+func runJob(job GenerateJob, start time.Time) (*rsa.PrivateKey, error) {
+	report := func(stage GenerateStage) {
+		if job.OnProgress != nil {
+			job.OnProgress(GenerateProgress{Job: job, Stage: stage, Elapsed: time.Since(start)})
+		}
+	}
+
+	report(GenerateStarted)
+	if job.Path == "" {
+		return GetPrivateKey(job.Bits)
+	}
+
+	format := job.Format
+	if format == 0 {
+		format = currentConfig().PrivateFormat
+	}
+	if format != PKCS1 && format != PKCS8 {
+		return nil, fmt.Errorf("%w: %s is not a private key format", errParse, format)
+	}
+
+	key, err := generateKey(job.Bits)
+	if err != nil {
+		return nil, err
+	}
+	report(GenerateWriting)
+	if err := writeKeypair(job.Path, job.Keyname, key, format, job.Options); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// writeKeypair writes the keypair like GeneratePKCS1Keypair and GeneratePKCS8Keypair
+func writeKeypair(path, keyname string, key *rsa.PrivateKey, format Format, opts []WriteOption) error {
+	if err := writePrivateKey(keyFilePath(path, keyname, privateSuffix), key, format, opts...); err != nil {
+		return err
+	}
+
+	return writePublicKey(keyFilePath(path, keyname, publicSuffix), &key.PublicKey, PKIX, opts...)
+}
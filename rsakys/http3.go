@@ -0,0 +1,118 @@
+package rsakys
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	alpnH3 = "h3"
+	// tickets stay decryptable for this many rotation intervals
+	ticketKeyGenerations = 3
+	minTicketSecretSize  = 32
+)
+
+var (
+	errTicketInterval = errors.New("session ticket key rotation interval must be positive")
+	errTicketSecret   = errors.New("session ticket secret is too short")
+)
+
+// HTTP3TLSConfig returns a server configuration for QUIC and HTTP/3 servers, e.g. quic-go's http3.Server,
+// based on ServerTLSConfig: TLS 1.3 only and the 'h3' ALPN protocol.
+// Session ticket keys are left to RotateSessionTicketKeys.
+func HTTP3TLSConfig(certPath, keyPath, caPath string) (*tls.Config, error) {
+	cfg, err := ServerTLSConfig(certPath, keyPath, caPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MinVersion = tls.VersionTLS13
+	cfg.NextProtos = []string{alpnH3}
+
+	return cfg, nil
+}
+
+// RotateSessionTicketKeys replaces the session ticket keys of cfg every interval, keeping the
+// previous two keys so recently issued tickets can still be resumed. With a shared secret of at least
+// 32 bytes the keys are derived from it and the current time, so all instances behind a load balancer
+// use the same keys; without one, random keys are generated. Rotations happen on multiples of interval
+// since the Unix epoch, so instances switch keys at the same time. Call stop to end the rotation.
+func RotateSessionTicketKeys(cfg *tls.Config, secret []byte, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, errTicketInterval
+	}
+	if len(secret) > 0 && len(secret) < minTicketSecretSize {
+		return nil, fmt.Errorf("%w: %d < %d bytes", errTicketSecret, len(secret), minTicketSecretSize)
+	}
+
+	var keys [][32]byte
+	next := func() error {
+		if len(secret) > 0 {
+			keys, _ = SessionTicketKeys(secret, interval, time.Now())
+		} else {
+			var key [32]byte
+			if _, err := io.ReadFull(currentConfig().Rand, key[:]); err != nil {
+				return err
+			}
+			keys = append([][32]byte{key}, keys[:min(len(keys), ticketKeyGenerations-1)]...)
+		}
+		cfg.SetSessionTicketKeys(keys)
+
+		return nil
+	}
+	if err := next(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		timer := time.NewTimer(untilNextInterval(interval))
+		defer timer.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-timer.C:
+				if err := next(); err != nil {
+					currentConfig().Logger.Printf("rsakys: session ticket key rotation failed: %v", err)
+				}
+				timer.Reset(untilNextInterval(interval))
+			}
+		}
+	}()
+
+	return func() { close(done) }, nil
+}
+
+// SessionTicketKeys derives the session ticket keys valid at t from a shared secret of at least 32 bytes,
+// the key of the current interval first
+func SessionTicketKeys(secret []byte, interval time.Duration, t time.Time) ([][32]byte, error) {
+	if len(secret) < minTicketSecretSize {
+		return nil, fmt.Errorf("%w: %d < %d bytes", errTicketSecret, len(secret), minTicketSecretSize)
+	}
+	if interval <= 0 {
+		return nil, errTicketInterval
+	}
+
+	epoch := t.UnixNano() / int64(interval)
+	keys := make([][32]byte, ticketKeyGenerations)
+	for i := range keys {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte("rsakys session ticket key"))
+		_ = binary.Write(mac, binary.BigEndian, epoch-int64(i))
+		copy(keys[i][:], mac.Sum(nil))
+	}
+
+	return keys, nil
+}
+
+// untilNextInterval returns the time left until the next multiple of interval since the Unix epoch,
+// where SessionTicketKeys starts the next key
+func untilNextInterval(interval time.Duration) time.Duration {
+	return interval - time.Duration(time.Now().UnixNano()%int64(interval))
+}
@@ -0,0 +1,65 @@
+package rsakys
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+// KEMSecretSize is the size of the shared secret established by Encapsulate in bytes
+const KEMSecretSize = 32
+
+var errEncapsulation = errors.New("encapsulation is invalid for this key")
+
+// Encapsulate establishes a shared secret for a given RSA public key following RSA-KEM (ISO 18033-2):
+// a random integer z < n is encrypted with raw RSA as encapsulation, and the 32 byte shared secret
+// is derived from z with KDF2 over SHA-256. Send the encapsulation, keep the secret.
+func Encapsulate(publicKey *rsa.PublicKey) (sharedSecret, encapsulation []byte, err error) {
+	z, err := rand.Int(currentConfig().Rand, publicKey.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(big.Int).Exp(z, big.NewInt(int64(publicKey.E)), publicKey.N)
+	size := publicKey.Size()
+
+	return kdf2(z.FillBytes(make([]byte, size)), KEMSecretSize), c.FillBytes(make([]byte, size)), nil
+}
+
+// Decapsulate recovers the shared secret of an encapsulation produced by Encapsulate
+func Decapsulate(privateKey *rsa.PrivateKey, encapsulation []byte) ([]byte, error) {
+	size := privateKey.Size()
+	if len(encapsulation) != size {
+		return nil, errEncapsulation
+	}
+	c := new(big.Int).SetBytes(encapsulation)
+	if c.Cmp(privateKey.N) >= 0 {
+		return nil, errEncapsulation
+	}
+
+	z, err := rsaSignRaw(privateKey, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return kdf2(z.FillBytes(make([]byte, size)), KEMSecretSize), nil
+}
+
+// kdf2 derives size bytes from z as KDF2 of ISO 18033-2 with SHA-256 and a counter starting at 1
+func kdf2(z []byte, size int) []byte {
+	out := make([]byte, 0, size+sha256.Size)
+
+	var counter [4]byte
+	for i := uint32(1); len(out) < size; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h := sha256.New()
+		h.Write(z)
+		h.Write(counter[:])
+		out = h.Sum(out)
+	}
+
+	return out[:size]
+}
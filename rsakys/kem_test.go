@@ -0,0 +1,101 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestKEMRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, encapsulation, err := Encapsulate(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret) != KEMSecretSize || len(encapsulation) != key.Size() {
+		t.Fatalf("got a %d byte secret and a %d byte encapsulation", len(secret), len(encapsulation))
+	}
+
+	got, err := Decapsulate(key, encapsulation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, secret) {
+		t.Fatal("decapsulated secret differs")
+	}
+
+	// the secret is KDF2-SHA256(I2OSP(z, k) || 00000001) of z = c^d mod n
+	z := new(big.Int).Exp(new(big.Int).SetBytes(encapsulation), key.D, key.N)
+	want := sha256.Sum256(append(z.FillBytes(make([]byte, key.Size())), 0, 0, 0, 1))
+	if !bytes.Equal(secret, want[:]) {
+		t.Fatal("secret does not follow RSA-KEM with KDF2-SHA256")
+	}
+
+	// raw RSA has no integrity, another key yields an unrelated secret
+	if c := new(big.Int).SetBytes(encapsulation); c.Cmp(other.N) < 0 {
+		wrong, err := Decapsulate(other, encapsulation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(wrong, secret) {
+			t.Fatal("another key recovered the secret")
+		}
+	}
+
+	another, _, err := Encapsulate(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(another, secret) {
+		t.Fatal("two encapsulations share a secret")
+	}
+}
+
+func TestDecapsulateErrors(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		encapsulation []byte
+	}{
+		{"empty", nil},
+		{"short", make([]byte, key.Size()-1)},
+		{"long", make([]byte, key.Size()+1)},
+		{"above modulus", bytes.Repeat([]byte{0xff}, key.Size())},
+		{"modulus", key.N.Bytes()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decapsulate(key, tt.encapsulation); !errors.Is(err, errEncapsulation) {
+				t.Fatalf("expected errEncapsulation, got %v", err)
+			}
+		})
+	}
+}
+
+func TestKDF2(t *testing.T) {
+	z := []byte("shared")
+	first := sha256.Sum256(append(bytes.Clone(z), 0, 0, 0, 1))
+	second := sha256.Sum256(append(bytes.Clone(z), 0, 0, 0, 2))
+	want := append(first[:], second[:8]...)
+
+	if got := kdf2(z, 40); !bytes.Equal(got, want) {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+	if got := kdf2(z, 16); !bytes.Equal(got, first[:16]) {
+		t.Fatalf("got %x", got)
+	}
+}
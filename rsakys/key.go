@@ -0,0 +1,71 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+)
+
+// Key is an algorithm independent private key, so code written against it
+// can switch algorithms by configuration.
+// RSA is the only implementation so far, EC and Ed25519 keys will follow.
+type Key interface {
+	// Algorithm returns the name of the key algorithm, e.g. 'RSA'
+	Algorithm() string
+	// Public returns the public half of the key
+	Public() crypto.PublicKey
+	// Fingerprint returns the 'SHA256:' fingerprint of the public key
+	Fingerprint() (string, error)
+	// PEM encodes the private key in the given format
+	PEM(format Format, opts ...WriteOption) ([]byte, error)
+}
+
+// ErrUnsupportedAlgorithm is returned for algorithm names no Key implementation exists for
+var ErrUnsupportedAlgorithm = errors.New("unsupported key algorithm")
+
+// RSAKey implements Key for RSA private keys
+type RSAKey struct {
+	*rsa.PrivateKey
+}
+
+var _ Key = RSAKey{}
+
+// NewRSAKey wraps an RSA private key as Key
+func NewRSAKey(key *rsa.PrivateKey) RSAKey {
+	return RSAKey{PrivateKey: key}
+}
+
+// NewKey generates a key of the named algorithm, size is the bit size for RSA keys
+func NewKey(algorithm string, size int) (Key, error) {
+	switch algorithm {
+	case algorithmRSA:
+		key, err := generateKey(size)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSAKey(key), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+// Algorithm returns 'RSA'
+func (k RSAKey) Algorithm() string {
+	return algorithmRSA
+}
+
+// Public returns the *rsa.PublicKey of the key
+func (k RSAKey) Public() crypto.PublicKey {
+	return &k.PublicKey
+}
+
+// Fingerprint returns the 'SHA256:' fingerprint of the public key
+func (k RSAKey) Fingerprint() (string, error) {
+	return Fingerprint(&k.PublicKey)
+}
+
+// PEM encodes the private key as PKCS1 or PKCS8 PEM block
+func (k RSAKey) PEM(format Format, opts ...WriteOption) ([]byte, error) {
+	return encodePrivateKey(k.PrivateKey, format, opts...)
+}
@@ -0,0 +1,151 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrKeyNotFound is returned when a keystore holds no key for a name or fingerprint
+var ErrKeyNotFound = errors.New("key not found")
+
+// Keystore resolves public keys by name or fingerprint
+type Keystore interface {
+	// Public returns the public key stored under name
+	Public(name string) (*rsa.PublicKey, error)
+	// Names returns the sorted names of all stored keys
+	Names() []string
+	// LookupByFingerprint returns the name and public key matching a 'SHA256:' fingerprint or key ID
+	LookupByFingerprint(fp string) (string, *rsa.PublicKey, error)
+}
+
+// DirKeystore is a Keystore backed by the '.pub' files of a directory.
+// Keys are loaded once and indexed by fingerprint, call Reload to pick up changes.
+type DirKeystore struct {
+	dir string
+
+	mu    sync.RWMutex
+	keys  map[string]*rsa.PublicKey
+	index map[string]string
+	kids  map[string]string
+}
+
+var _ Keystore = (*DirKeystore)(nil)
+
+// NewDirKeystore loads all public keys of dir into a keystore
+func NewDirKeystore(dir string) (*DirKeystore, error) {
+	ks := &DirKeystore{dir: dir}
+	if err := ks.Reload(); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// Reload rescans the directory and rebuilds the fingerprint index.
+// Keys are indexed by fingerprint, thumbprint, and their 'Key-ID' header,
+// files holding the same key are indexed under the first name in sort order and share its key.
+// Key-IDs are declared by the files themselves and kept apart, so they never shadow a computed fingerprint.
+func (ks *DirKeystore) Reload() error {
+	keys := make(map[string]*rsa.PublicKey)
+	kids := make(map[string]string)
+	err := scanDir(ks.dir, publicSuffix, func(name string, cntnt []byte) {
+		if key, headers, err := parsePublicBlock(cntnt); err == nil {
+			keys[name] = key
+			kids[name] = headers[keyIDHeader]
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	index := make(map[string]string, 2*len(keys))
+	kidIndex := make(map[string]string, len(keys))
+	for _, name := range names {
+		fp, err := Fingerprint(keys[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		thumbprint, err := Thumbprint(keys[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		// deduplicate files holding the same key, the fingerprint only preselects the candidate
+		if first, ok := index[fp]; ok && EqualPublic(keys[first], keys[name]) {
+			keys[name] = keys[first]
+		}
+		for _, id := range []string{fp, thumbprint} {
+			if _, ok := index[id]; !ok {
+				index[id] = name
+			}
+		}
+		if _, ok := kidIndex[kids[name]]; kids[name] != "" && !ok {
+			kidIndex[kids[name]] = name
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.index = index
+	ks.kids = kidIndex
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// Public returns the public key stored under name
+func (ks *DirKeystore) Public(name string) (*rsa.PublicKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, name)
+	}
+
+	return key, nil
+}
+
+// Names returns the sorted names of all stored keys
+func (ks *DirKeystore) Names() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	names := make([]string, 0, len(ks.keys))
+	for name := range ks.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LookupByFingerprint returns the name and public key matching a fingerprint or key ID,
+// the 'SHA256:' prefix of fingerprints is optional. Fingerprints and thumbprints take precedence over key IDs.
+func (ks *DirKeystore) LookupByFingerprint(fp string) (string, *rsa.PublicKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	name, ok := ks.index[fp]
+	if !ok && !strings.HasPrefix(fp, "SHA256:") {
+		name, ok = ks.index["SHA256:"+fp]
+	}
+	if !ok {
+		name, ok = ks.kids[fp]
+	}
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrKeyNotFound, fp)
+	}
+
+	return name, ks.keys[name], nil
+}
@@ -0,0 +1,62 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirKeystoreDeduplicatesKeys(t *testing.T) {
+	dir := t.TempDir()
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, pub := range map[string]*rsa.PublicKey{"a": &key.PublicKey, "b": &key.PublicKey, "c": &other.PublicKey} {
+		if err := WritePKIXPublicKey(pub, filepath.Join(dir, name+".pub"), WithKeyID("kid-"+name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ks, err := NewDirKeystore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := ks.Public("a")
+	b, _ := ks.Public("b")
+	c, _ := ks.Public("c")
+	if a != b {
+		t.Fatal("files holding the same key do not share it")
+	}
+	if !EqualPublic(a, &key.PublicKey) || EqualPublic(a, c) {
+		t.Fatal("keys did not round-trip through the keystore")
+	}
+
+	fp, err := Fingerprint(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		id   string
+		name string
+	}{
+		{fp, "a"},
+		{fp[len("SHA256:"):], "a"},
+		{"kid-b", "b"},
+		{"kid-c", "c"},
+	}
+	for _, tt := range tests {
+		name, _, err := ks.LookupByFingerprint(tt.id)
+		if err != nil || name != tt.name {
+			t.Errorf("lookup of %s returned %q, %v, want %q", tt.id, name, err, tt.name)
+		}
+	}
+	if _, _, err := ks.LookupByFingerprint("unknown"); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrKeyNotFound)
+	}
+}
@@ -0,0 +1,73 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+)
+
+const keyIDHeader = "Key-ID"
+
+// Thumbprint returns the RFC 7638 JWK thumbprint of a public key,
+// the base64url encoded SHA-256 digest of its canonical JWK, used as default key ID
+func Thumbprint(publicKey *rsa.PublicKey) (string, error) {
+	// members in lexicographic order without whitespace as required by RFC 7638
+	canonical, err := json.Marshal(struct {
+		E   string `json:"e"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+	}{
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		Kty: algorithmRSA,
+		N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// ReadKeyID returns the 'Key-ID' header of a private or public key PEM file,
+// keys written without one are identified by their thumbprint
+func ReadKeyID(path string) (string, error) {
+	cntnt, err := readFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	var dk DiscoveredKey
+	detectKey(cntnt, &dk)
+	if dk.Err != nil {
+		return "", dk.Err
+	}
+	if dk.KeyID != "" {
+		return dk.KeyID, nil
+	}
+
+	return Thumbprint(dk.PublicKey)
+}
+
+// applyKeyID sets the 'Key-ID' header if a key ID was requested
+func (o *writeOptions) applyKeyID(publicKey *rsa.PublicKey) error {
+	kid := o.keyID
+	if kid == "" && o.autoKeyID {
+		var err error
+		if kid, err = Thumbprint(publicKey); err != nil {
+			return err
+		}
+	}
+	if kid == "" {
+		return nil
+	}
+
+	if o.headers == nil {
+		o.headers = make(map[string]string, 1)
+	}
+	o.headers[keyIDHeader] = kid
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package rsakys
+
+// This file holds the OpenSSL "traditional" encrypted PEM format (Proc-Type/DEK-Info headers).
+// It only exists for legacy consumers, e.g. old appliances or Ruby/PHP stacks, that cannot read
+// encrypted PKCS8. Its key derivation (EVP_BytesToKey, a single MD5 round) is weak by today's
+// standards and its CBC mode is unauthenticated, so it must not be used for anything else.
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	legacyProcType = "4,ENCRYPTED"
+	legacyCipher   = "AES-256-CBC"
+)
+
+var (
+	errLegacyNotEncrypted = errors.New("PEM block is not a legacy encrypted block")
+	errLegacyCipher       = errors.New("unsupported legacy PEM cipher")
+	errLegacyDecrypt      = errors.New("legacy PEM decryption failed, wrong password or corrupt data")
+)
+
+// GetLegacyEncryptedPKCS1PrivateKeyString returns the PKCS1 byterepresentation of a given RSA private key struct
+// as AES-256-CBC encrypted PEM block with OpenSSL's Proc-Type and DEK-Info headers.
+// Legacy compatibility only, prefer encrypted PKCS8 whenever the consumer supports it.
+func GetLegacyEncryptedPKCS1PrivateKeyString(privateKey *rsa.PrivateKey, password []byte, opts ...WriteOption) ([]byte, error) {
+	o := newWriteOptions(opts)
+	block, err := legacyEncryptedBlock(privateKey, password, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.encode(block)
+}
+
+// WriteLegacyEncryptedPKCS1PrivateKey writes a given RSA private key as AES-256-CBC encrypted PKCS1 PEM block
+// with OpenSSL's Proc-Type and DEK-Info headers to disc.
+// Legacy compatibility only, prefer encrypted PKCS8 whenever the consumer supports it.
+func WriteLegacyEncryptedPKCS1PrivateKey(privateKey *rsa.PrivateKey, path string, password []byte, opts ...WriteOption) error {
+	o := newWriteOptions(opts)
+	block, err := legacyEncryptedBlock(privateKey, password, o)
+	if err != nil {
+		return err
+	}
+	encoded, err := o.encode(block)
+	if err != nil {
+		return err
+	}
+
+	return writeKeyFile(path, currentConfig().PrivatePerm, encoded, o)
+}
+
+// ReadLegacyEncryptedPrivate reads a private key PEM file encrypted with OpenSSL's traditional
+// AES-256-CBC format and returns the private key struct
+func ReadLegacyEncryptedPrivate(path string, password []byte) (*rsa.PrivateKey, error) {
+	cntnt, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(cntnt)
+	if block == nil {
+		return nil, errParse
+	}
+	if block.Type != privateType {
+		return nil, errWrongPrivateType
+	}
+	if block.Headers["Proc-Type"] != legacyProcType {
+		return nil, errLegacyNotEncrypted
+	}
+
+	name, ivHex, ok := strings.Cut(block.Headers["DEK-Info"], ",")
+	if !ok || name != legacyCipher {
+		return nil, errLegacyCipher
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil || len(iv) != aes.BlockSize {
+		return nil, errLegacyCipher
+	}
+	if len(block.Bytes) == 0 || len(block.Bytes)%aes.BlockSize != 0 {
+		return nil, errLegacyDecrypt
+	}
+
+	c, err := aes.NewCipher(legacyKey(password, iv[:8]))
+	if err != nil {
+		return nil, err
+	}
+	der := make([]byte, len(block.Bytes))
+	cipher.NewCBCDecrypter(c, iv).CryptBlocks(der, block.Bytes)
+
+	pad := int(der[len(der)-1])
+	if pad == 0 || pad > aes.BlockSize || !bytes.Equal(der[len(der)-pad:], bytes.Repeat([]byte{byte(pad)}, pad)) {
+		return nil, errLegacyDecrypt
+	}
+
+	privateKey, err := x509.ParsePKCS1PrivateKey(der[:len(der)-pad])
+	if err != nil {
+		return nil, errLegacyDecrypt
+	}
+
+	return privateKey, nil
+}
+
+func legacyEncryptedBlock(key *rsa.PrivateKey, password []byte, o *writeOptions) (*pem.Block, error) {
+	der := x509.MarshalPKCS1PrivateKey(key)
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(currentConfig().Rand, iv); err != nil {
+		return nil, err
+	}
+
+	c, err := aes.NewCipher(legacyKey(password, iv[:8]))
+	if err != nil {
+		return nil, err
+	}
+
+	pad := aes.BlockSize - len(der)%aes.BlockSize
+	data := append(der, bytes.Repeat([]byte{byte(pad)}, pad)...)
+	cipher.NewCBCEncrypter(c, iv).CryptBlocks(data, data)
+
+	headers := make(map[string]string, len(o.headers)+2)
+	for k, v := range o.headers {
+		headers[k] = v
+	}
+	headers["Proc-Type"] = legacyProcType
+	headers["DEK-Info"] = legacyCipher + "," + strings.ToUpper(hex.EncodeToString(iv))
+
+	return &pem.Block{
+		Type:    privateType,
+		Headers: headers,
+		Bytes:   data,
+	}, nil
+}
+
+// legacyKey derives the 32 byte AES key like OpenSSL's EVP_BytesToKey with MD5 and a single round
+func legacyKey(password, salt []byte) []byte {
+	var key, prev []byte
+	for len(key) < 32 {
+		h := md5.New()
+		h.Write(prev)
+		h.Write(password)
+		h.Write(salt)
+		prev = h.Sum(nil)
+		key = append(key, prev...)
+	}
+
+	return key[:32]
+}
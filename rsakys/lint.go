@@ -0,0 +1,200 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// LintSeverity classifies a lint finding
+type LintSeverity uint
+
+// Lint severities
+const (
+	// LintWarning marks keys that work but violate current recommendations
+	LintWarning LintSeverity = iota
+	// LintError marks malformed or insecure keys that should be rejected
+	LintError
+)
+
+// String returns the name of the severity
+func (s LintSeverity) String() string {
+	if s == LintError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// LintFinding is a single issue found by Lint
+type LintFinding struct {
+	Severity LintSeverity
+	Check    string
+	Message  string
+}
+
+// LintReport is the structured result of Lint and LintPublic
+type LintReport struct {
+	Bits     int
+	Findings []LintFinding
+}
+
+// OK reports whether the report holds no errors, warnings are tolerated
+func (r *LintReport) OK() bool {
+	for _, f := range r.Findings {
+		if f.Severity == LintError {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Error implements the error interface by summarizing all findings
+func (r *LintReport) Error() string {
+	msgs := make([]string, len(r.Findings))
+	for i, f := range r.Findings {
+		msgs[i] = fmt.Sprintf("%s: %s: %s", f.Severity, f.Check, f.Message)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Err returns the report as error if it holds any errors, nil otherwise
+func (r *LintReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+
+	return r
+}
+
+func (r *LintReport) add(severity LintSeverity, check, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, LintFinding{
+		Severity: severity,
+		Check:    check,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// LintPublic checks a given RSA public key for a sufficient modulus size and a sane exponent
+func LintPublic(publicKey *rsa.PublicKey) *LintReport {
+	r := &LintReport{}
+	if publicKey == nil || publicKey.N == nil {
+		r.add(LintError, "modulus", "key has no modulus")
+		return r
+	}
+	r.Bits = publicKey.N.BitLen()
+
+	lintModulus(r, publicKey.N)
+	lintExponent(r, publicKey.E)
+
+	return r
+}
+
+// Lint checks a given RSA private key: it runs rsa.Validate, checks the public part like LintPublic,
+// checks prime sizes, balance, and distance, and verifies d against e and φ(n)
+func Lint(privateKey *rsa.PrivateKey) *LintReport {
+	r := LintPublic(&privateKey.PublicKey)
+	if privateKey.N == nil {
+		return r
+	}
+
+	if err := privateKey.Validate(); err != nil {
+		r.add(LintError, "validate", "%v", err)
+	}
+
+	if len(privateKey.Primes) != 2 {
+		r.add(LintWarning, "primes", "key uses %d primes, most implementations expect 2", len(privateKey.Primes))
+	}
+	for i, p := range privateKey.Primes {
+		if !p.ProbablyPrime(20) {
+			r.add(LintError, "primes", "prime %d is composite", i)
+		}
+	}
+
+	if len(privateKey.Primes) == 2 {
+		lintPrimePair(r, privateKey.N, privateKey.Primes[0], privateKey.Primes[1])
+		lintPrivateExponent(r, privateKey)
+	}
+
+	return r
+}
+
+func lintModulus(r *LintReport, n *big.Int) {
+	if n.Bit(0) == 0 {
+		r.add(LintError, "modulus", "modulus is even")
+	}
+	if r.Bits < MinBits() {
+		r.add(LintError, "modulus", "modulus has %d bits, at least %d are required", r.Bits, MinBits())
+	}
+	if r.Bits%8 != 0 {
+		r.add(LintWarning, "modulus", "modulus size %d is not a multiple of 8", r.Bits)
+	}
+}
+
+func lintExponent(r *LintReport, e int) {
+	switch {
+	case e < 3:
+		r.add(LintError, "exponent", "public exponent %d is invalid", e)
+	case e%2 == 0:
+		r.add(LintError, "exponent", "public exponent %d is even", e)
+	case e < 65537:
+		r.add(LintWarning, "exponent", "public exponent %d is small, 65537 is recommended", e)
+	case int64(e) > 1<<32:
+		r.add(LintWarning, "exponent", "public exponent %d is unusually large", e)
+	}
+}
+
+func lintPrimePair(r *LintReport, n, p, q *big.Int) {
+	half := n.BitLen() / 2
+	if pb, qb := p.BitLen(), q.BitLen(); pb != half && qb != half || absInt(pb-qb) > 1 {
+		r.add(LintWarning, "balance", "primes have %d and %d bits, expected about %d each", pb, qb, half)
+	}
+
+	// |p - q| < 2^(n/2 - 100) allows factoring with Fermat's method
+	diff := new(big.Int).Sub(p, q)
+	if diff.Abs(diff).BitLen() <= half-100 {
+		r.add(LintError, "distance", "primes are too close to each other, the modulus can be factored")
+	}
+
+	if new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		r.add(LintError, "modulus", "modulus is not the product of the primes")
+	}
+}
+
+func lintPrivateExponent(r *LintReport, key *rsa.PrivateKey) {
+	one := big.NewInt(1)
+	pm1 := new(big.Int).Sub(key.Primes[0], one)
+	qm1 := new(big.Int).Sub(key.Primes[1], one)
+	phi := new(big.Int).Mul(pm1, qm1)
+	lambda := new(big.Int).Div(phi, new(big.Int).GCD(nil, nil, pm1, qm1))
+
+	e := big.NewInt(int64(key.E))
+	if new(big.Int).GCD(nil, nil, e, phi).Cmp(one) != 0 {
+		r.add(LintError, "exponent", "public exponent is not coprime to φ(n)")
+		return
+	}
+
+	ed := new(big.Int).Mul(e, key.D)
+	if new(big.Int).Mod(ed, lambda).Cmp(one) != 0 {
+		r.add(LintError, "private exponent", "d is not the inverse of e modulo λ(n)")
+	}
+	if key.D.Cmp(phi) >= 0 {
+		r.add(LintWarning, "private exponent", "d is not reduced modulo φ(n)")
+	}
+
+	// Wiener's attack recovers d < n^0.25 / 3
+	if key.D.BitLen() <= r.Bits/4 {
+		r.add(LintError, "private exponent", "d is small enough to be recovered from the public key")
+	}
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
@@ -0,0 +1,253 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/abecodes/goutls/checksum"
+)
+
+const (
+	manifestVersion = "rsakys-manifest-3"
+	// ManifestFile is skipped when a tree is hashed, so a manifest can be shipped inside the tree it describes
+	ManifestFile = "rsakys-manifest.json"
+)
+
+// Errors of signed manifests
+var (
+	ErrManifestSignature = errors.New("manifest signature is invalid")
+	ErrManifestMismatch  = errors.New("file tree does not match the manifest")
+)
+
+// Modes of manifest entries, permissions besides the executable bit depend on the umask
+// and the OS and are not recorded
+const (
+	ManifestModeFile       = "file"
+	ManifestModeExecutable = "executable"
+	ManifestModeSymlink    = "symlink"
+)
+
+// ManifestEntry describes a single file of a signed manifest, symlinks are recorded with their
+// target instead of a digest. Other file types, e.g. pipes, record their type as mode.
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Mode   string `json:"mode"`
+	Size   int64  `json:"size,omitempty"`
+	Digest string `json:"digest,omitempty"`
+	Target string `json:"target,omitempty"`
+}
+
+// Manifest lists all files and symlinks of a tree with their type, size, and SHA-256 digest or link target,
+// signed with RSA-PSS over a canonical encoding of the entries
+type Manifest struct {
+	Version     string          `json:"version"`
+	Algorithm   string          `json:"algorithm"`
+	Fingerprint string          `json:"fingerprint"`
+	Files       []ManifestEntry `json:"files"`
+	Signature   []byte          `json:"signature"`
+}
+
+// ManifestDiff lists the paths in which a tree differs from its manifest
+type ManifestDiff struct {
+	Added    []string
+	Removed  []string
+	Modified []string
+}
+
+// OK reports whether the tree matched the manifest
+func (d *ManifestDiff) OK() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// CreateManifest hashes all files below dir and returns the signed manifest as JSON
+func CreateManifest(dir string, privateKey *rsa.PrivateKey) ([]byte, error) {
+	if err := checkSigningKey(privateKey.N); err != nil {
+		return nil, err
+	}
+	files, err := hashTree(dir)
+	if err != nil {
+		return nil, err
+	}
+	fp, err := Fingerprint(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manifest{
+		Version:     manifestVersion,
+		Algorithm:   string(checksum.SHA256),
+		Fingerprint: fp,
+		Files:       files,
+	}
+	digest := m.digest()
+	if m.Signature, err = rsa.SignPSS(currentConfig().Rand, privateKey, crypto.SHA256, digest[:], nil); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(m, "", "  ")
+}
+
+// VerifyManifest checks the signature of a manifest and compares it against the tree below dir.
+// A tree differing from the manifest returns the differences together with ErrManifestMismatch.
+func VerifyManifest(dir string, manifest []byte, publicKey *rsa.PublicKey) (*ManifestDiff, error) {
+	var m Manifest
+	if err := json.Unmarshal(manifest, &m); err != nil {
+		return nil, err
+	}
+	if m.Version != manifestVersion || m.Algorithm != string(checksum.SHA256) {
+		return nil, fmt.Errorf("%w: unsupported version %q or algorithm %q", ErrManifestSignature, m.Version, m.Algorithm)
+	}
+	digest := m.digest()
+	if err := rsa.VerifyPSS(publicKey, crypto.SHA256, digest[:], m.Signature, nil); err != nil {
+		return nil, ErrManifestSignature
+	}
+
+	files, err := hashTree(dir)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffEntries(m.Files, files)
+	if !diff.OK() {
+		return diff, ErrManifestMismatch
+	}
+
+	return diff, nil
+}
+
+// digest hashes the canonical encoding of the entries, the signature does not depend on JSON formatting
+func (m *Manifest) digest() [sha256.Size]byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s\n%s\n%s\n", m.Version, m.Algorithm, m.Fingerprint)
+	for _, f := range m.Files {
+		fmt.Fprintf(&buf, "%s %s %d %q %q\n", f.Mode, f.Digest, f.Size, f.Path, f.Target)
+	}
+
+	return sha256.Sum256(buf.Bytes())
+}
+
+// hashTree records every non-directory below dir. A symlinked dir itself, e.g. 'current' pointing to
+// a release, is resolved, symlinks within the tree are recorded but not followed.
+func hashTree(dir string) ([]ManifestEntry, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	root = fixPath(root)
+
+	var files []ManifestEntry
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == ManifestFile && d.Type().IsRegular() {
+			return err
+		}
+
+		entry, err := manifestEntry(p, d)
+		if err != nil {
+			return err
+		}
+		entry.Path = filepath.ToSlash(rel)
+		files = append(files, entry)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+func manifestEntry(p string, d fs.DirEntry) (ManifestEntry, error) {
+	info, err := d.Info()
+	if err != nil {
+		return ManifestEntry{}, err
+	}
+	entry := ManifestEntry{Mode: info.Mode().Type().String()}
+
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		entry.Mode = ManifestModeSymlink
+		target, err := os.Readlink(p)
+		if err != nil {
+			return ManifestEntry{}, err
+		}
+		entry.Target = filepath.ToSlash(target)
+	case info.Mode().IsRegular():
+		sums, err := checksum.HashFile(p, checksum.SHA256)
+		if err != nil {
+			return ManifestEntry{}, err
+		}
+		entry.Mode = ManifestModeFile
+		if info.Mode()&0o111 != 0 {
+			entry.Mode = ManifestModeExecutable
+		}
+		entry.Size = info.Size()
+		entry.Digest = sums[checksum.SHA256]
+	}
+
+	return entry, nil
+}
+
+func diffEntries(expected, actual []ManifestEntry) *ManifestDiff {
+	found := make(map[string]ManifestEntry, len(actual))
+	for _, f := range actual {
+		found[f.Path] = f
+	}
+
+	diff := &ManifestDiff{}
+	for _, e := range expected {
+		f, ok := found[e.Path]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, e.Path)
+		case !sameEntry(e, f):
+			diff.Modified = append(diff.Modified, e.Path)
+		}
+		delete(found, e.Path)
+	}
+	for p := range found {
+		diff.Added = append(diff.Added, p)
+	}
+	sort.Strings(diff.Added)
+
+	return diff
+}
+
+// sameEntry compares two entries, ignoring the executable bit on file systems not recording it
+func sameEntry(expected, actual ManifestEntry) bool {
+	if !executableBits && expected.Mode == ManifestModeExecutable {
+		expected.Mode = ManifestModeFile
+	}
+
+	return expected == actual
+}
+
+// WriteManifestFile creates the signed manifest of dir and stores it as ManifestFile inside dir
+func WriteManifestFile(dir string, privateKey *rsa.PrivateKey) error {
+	manifest, err := CreateManifest(dir, privateKey)
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(filepath.Join(dir, ManifestFile), currentConfig().PublicPerm, func(w io.Writer) error {
+		_, err := w.Write(manifest)
+		return err
+	})
+}
@@ -0,0 +1,108 @@
+package rsakys
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestManifest(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "a.txt"), "a", 0o644)
+	mustWrite(t, filepath.Join(dir, "sub", "b.txt"), "b", 0o600)
+	mustWrite(t, filepath.Join(dir, "run.sh"), "#!/bin/sh", 0o755)
+
+	if err := WriteManifestFile(dir, key); err != nil {
+		t.Fatal(err)
+	}
+	manifest, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff, err := VerifyManifest(dir, manifest, &key.PublicKey); err != nil || !diff.OK() {
+		t.Fatalf("unchanged tree: %v %+v", err, diff)
+	}
+	if _, err := VerifyManifest(dir, manifest, &other.PublicKey); !errors.Is(err, ErrManifestSignature) {
+		t.Fatalf("expected ErrManifestSignature for another key, got %v", err)
+	}
+
+	// permissions besides the executable bit do not count
+	if err := os.Chmod(filepath.Join(dir, "a.txt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if diff, err := VerifyManifest(dir, manifest, &key.PublicKey); err != nil || !diff.OK() {
+		t.Fatalf("changed permissions: %v %+v", err, diff)
+	}
+
+	mustWrite(t, filepath.Join(dir, "a.txt"), "changed", 0o644)
+	mustWrite(t, filepath.Join(dir, "c.txt"), "c", 0o644)
+	if err := os.Remove(filepath.Join(dir, "sub", "b.txt")); err != nil {
+		t.Fatal(err)
+	}
+	want := &ManifestDiff{Added: []string{"c.txt"}, Removed: []string{"sub/b.txt"}, Modified: []string{"a.txt"}}
+	if executableBits {
+		if err := os.Chmod(filepath.Join(dir, "run.sh"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		want.Modified = append(want.Modified, "run.sh")
+	}
+
+	diff, err := VerifyManifest(dir, manifest, &key.PublicKey)
+	if !errors.Is(err, ErrManifestMismatch) {
+		t.Fatalf("expected ErrManifestMismatch, got %v", err)
+	}
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("got %+v, want %+v", diff, want)
+	}
+}
+
+func TestManifestEntryModes(t *testing.T) {
+	dir := t.TempDir()
+	mustWrite(t, filepath.Join(dir, "file"), "x", 0o640)
+	mustWrite(t, filepath.Join(dir, "exec"), "x", 0o750)
+	if err := os.Symlink("file", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	files, err := hashTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modes := make(map[string]string)
+	for _, f := range files {
+		modes[f.Path] = f.Mode
+	}
+
+	want := map[string]string{"exec": ManifestModeExecutable, "file": ManifestModeFile, "link": ManifestModeSymlink}
+	if !executableBits {
+		want["exec"] = ManifestModeFile
+	}
+	if !reflect.DeepEqual(modes, want) {
+		t.Fatalf("got %v, want %v", modes, want)
+	}
+}
+
+func mustWrite(t *testing.T, path, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+}
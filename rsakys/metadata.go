@@ -0,0 +1,150 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	metadataSuffix = "json"
+	algorithmRSA   = "RSA"
+)
+
+// Metadata describes a key file and is stored as a JSON sidecar next to it
+type Metadata struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	Algorithm   string     `json:"algorithm"`
+	Bits        int        `json:"bits"`
+	Usage       string     `json:"usage,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	Fingerprint string     `json:"fingerprint"`
+	KeyID       string     `json:"kid,omitempty"`
+	NotAfter    *time.Time `json:"not_after,omitempty"`
+}
+
+// Fingerprint returns the SHA-256 fingerprint of the PKIX encoded public key
+// in the form 'SHA256:<base64>'
+func Fingerprint(publicKey *rsa.PublicKey) (string, error) {
+	sum, err := fingerprint(publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum), nil
+}
+
+// FingerprintBytes returns the raw SHA-256 digest of the PKIX encoded public key,
+// e.g. to render it with one of the encodings of the encode package
+func FingerprintBytes(publicKey *rsa.PublicKey) ([]byte, error) {
+	return fingerprint(publicKey)
+}
+
+// NewMetadata returns the metadata of a given RSA public key struct
+// with the creation time set to now
+func NewMetadata(publicKey *rsa.PublicKey, usage, comment string) (*Metadata, error) {
+	fp, err := Fingerprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := Thumbprint(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		CreatedAt:   time.Now().UTC(),
+		Algorithm:   algorithmRSA,
+		Bits:        publicKey.N.BitLen(),
+		Usage:       usage,
+		Comment:     comment,
+		Fingerprint: fp,
+		KeyID:       kid,
+	}, nil
+}
+
+// MetadataPath returns the path of the metadata sidecar belonging to a key file
+func MetadataPath(keyPath string) string {
+	return keyPath + "." + metadataSuffix
+}
+
+// WriteMetadata atomically writes the metadata sidecar of a key file to disc
+func WriteMetadata(keyPath string, md *Metadata) error {
+	cntnt, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeAtomic(MetadataPath(keyPath), currentConfig().PublicPerm, func(w io.Writer) error {
+		_, err := w.Write(append(cntnt, '\n'))
+		return err
+	})
+}
+
+// ReadMetadata reads the metadata sidecar of a key file
+func ReadMetadata(keyPath string) (*Metadata, error) {
+	cntnt, err := readFile(MetadataPath(keyPath))
+	if err != nil {
+		return nil, err
+	}
+
+	md := &Metadata{}
+	if err := json.Unmarshal(cntnt, md); err != nil {
+		return nil, err
+	}
+
+	return md, nil
+}
+
+// UpdateMetadata reads the metadata sidecar of a key file,
+// applies the given update, and writes it back to disc
+func UpdateMetadata(keyPath string, update func(md *Metadata)) error {
+	md, err := ReadMetadata(keyPath)
+	if err != nil {
+		return err
+	}
+	update(md)
+
+	return WriteMetadata(keyPath, md)
+}
+
+// ScanMetadata reads all metadata sidecars of a directory
+// and returns them keyed by the path of their key file
+func ScanMetadata(dir string) (map[string]*Metadata, error) {
+	matches, err := globFiles(filepath.Join(dir, "*."+metadataSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := make(map[string]*Metadata, len(matches))
+	for _, m := range matches {
+		keyPath := trimSuffix(m, metadataSuffix)
+		if _, err := os.Stat(keyPath); err != nil {
+			continue
+		}
+
+		md, err := ReadMetadata(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		inventory[keyPath] = md
+	}
+
+	return inventory, nil
+}
+
+func fingerprint(key *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+
+	return sum[:], nil
+}
@@ -0,0 +1,77 @@
+package rsakys
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteWithMetadata(t *testing.T) {
+	dir := t.TempDir()
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp, err := Fingerprint(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		write func(path string) error
+	}{
+		{"private", filepath.Join(dir, "k.pem"), func(path string) error {
+			return WritePKCS8PrivateKey(key, path, WithMetadata("signing"), WithComment("ci"))
+		}},
+		{"public", filepath.Join(dir, "k.pub"), func(path string) error {
+			return WritePKIXPublicKey(&key.PublicKey, path, WithMetadata("signing"), WithComment("ci"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write(tt.path); err != nil {
+				t.Fatal(err)
+			}
+
+			md, err := ReadMetadata(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if md.Fingerprint != fp || md.Usage != "signing" || md.Comment != "ci" || md.Bits != Bits2048 {
+				t.Fatalf("unexpected metadata %+v", md)
+			}
+			info, err := os.Stat(MetadataPath(tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != currentConfig().PublicPerm {
+				t.Fatalf("sidecar has mode %v, want %v", info.Mode().Perm(), currentConfig().PublicPerm)
+			}
+		})
+	}
+
+	inventory, err := ScanMetadata(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory) != len(tests) {
+		t.Fatalf("scanned %d sidecars, want %d", len(inventory), len(tests))
+	}
+}
+
+func TestWriteWithoutMetadata(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "k.pem")
+	if err := WritePKCS8PrivateKey(key, path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(MetadataPath(path)); !os.IsNotExist(err) {
+		t.Fatalf("sidecar written without WithMetadata: %v", err)
+	}
+}
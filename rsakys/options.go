@@ -0,0 +1,145 @@
+package rsakys
+
+import (
+	"crypto"
+	_ "crypto/sha1" // SHA-1 is only registered for legacy OAEP interoperability
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"errors"
+	"strings"
+)
+
+// WriteOption configures how keys are encoded and written to disc
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	backup        bool
+	backupDir     string
+	headers       map[string]string
+	comment       string
+	keyID         string
+	autoKeyID     bool
+	deterministic bool
+	metadata      bool
+	usage         string
+}
+
+func newWriteOptions(opts []WriteOption) *writeOptions {
+	o := &writeOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithBackup copies an existing key file to '<path>.bak-<timestamp>' before it is replaced
+func WithBackup() WriteOption {
+	return func(o *writeOptions) {
+		o.backup = true
+	}
+}
+
+// WithBackupDir copies an existing key file into dir before it is replaced,
+// the directory is created if it does not exist
+func WithBackupDir(dir string) WriteOption {
+	return func(o *writeOptions) {
+		o.backup = true
+		o.backupDir = dir
+	}
+}
+
+// WithHeaders attaches the given headers, e.g. 'Comment' or 'Key-ID', to the PEM block.
+// 'Proc-Type' and 'DEK-Info' are reserved for encrypted PEM blocks and must not be used.
+func WithHeaders(headers map[string]string) WriteOption {
+	return func(o *writeOptions) {
+		if o.headers == nil {
+			o.headers = make(map[string]string, len(headers))
+		}
+		for k, v := range headers {
+			o.headers[k] = v
+		}
+	}
+}
+
+// WithComment appends a comment line, e.g. an email, hostname, or purpose, after the PEM block
+// of a public key, similar to the trailing comment of ssh-keygen output.
+// Line breaks are replaced with spaces, private keys ignore the comment.
+func WithComment(comment string) WriteOption {
+	return func(o *writeOptions) {
+		o.comment = strings.Join(strings.Fields(comment), " ")
+	}
+}
+
+// WithKeyID stores kid in the 'Key-ID' PEM header, so signatures and envelopes can reference the key
+func WithKeyID(kid string) WriteOption {
+	return func(o *writeOptions) {
+		o.keyID = strings.Join(strings.Fields(kid), " ")
+	}
+}
+
+// WithAutoKeyID stores the RFC 7638 thumbprint of the key in the 'Key-ID' PEM header,
+// a kid given with WithKeyID takes precedence
+func WithAutoKeyID() WriteOption {
+	return func(o *writeOptions) {
+		o.autoKeyID = true
+	}
+}
+
+// WithDeterministicPEM encodes byte-identical output for the same key and options, independent of
+// the Go version: headers sorted by name, base64 wrapped at 64 columns, LF line endings, and a single
+// trailing newline, so key files in config management do not produce spurious diffs
+func WithDeterministicPEM() WriteOption {
+	return func(o *writeOptions) {
+		o.deterministic = true
+	}
+}
+
+// WithMetadata writes the metadata sidecar '<path>.json' next to the key with the given intended usage,
+// the comment of WithComment is recorded as well
+func WithMetadata(usage string) WriteOption {
+	return func(o *writeOptions) {
+		o.metadata = true
+		o.usage = usage
+	}
+}
+
+// EncryptOption configures the RSA-OAEP parameters of the encryption helpers,
+// encryption and decryption must use the same options
+type EncryptOption func(*encryptOptions)
+
+type encryptOptions struct {
+	hash  crypto.Hash
+	label []byte
+}
+
+var errOAEPHash = errors.New("unsupported OAEP hash, use SHA-1, SHA-256, SHA-384, or SHA-512")
+
+func newEncryptOptions(opts []EncryptOption) (*encryptOptions, error) {
+	o := &encryptOptions{hash: crypto.SHA256}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	switch o.hash {
+	case crypto.SHA1, crypto.SHA256, crypto.SHA384, crypto.SHA512:
+		return o, nil
+	default:
+		return nil, errOAEPHash
+	}
+}
+
+// WithOAEPHash sets the hash used by RSA-OAEP and its MGF1, defaults to SHA-256.
+// SHA-1 is only supported for interoperability with legacy systems.
+func WithOAEPHash(h crypto.Hash) EncryptOption {
+	return func(o *encryptOptions) {
+		o.hash = h
+	}
+}
+
+// WithOAEPLabel sets the RSA-OAEP label, which binds ciphertexts to a context, defaults to none
+func WithOAEPLabel(label []byte) EncryptOption {
+	return func(o *encryptOptions) {
+		o.label = label
+	}
+}
@@ -0,0 +1,103 @@
+package rsakys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// PrivateKey lists the private key types Parse can return
+type PrivateKey interface {
+	*rsa.PrivateKey | *ecdsa.PrivateKey | ed25519.PrivateKey
+}
+
+// PublicKey lists the public key types ParsePublic can return
+type PublicKey interface {
+	*rsa.PublicKey | *ecdsa.PublicKey | ed25519.PublicKey
+}
+
+// ErrUnexpectedKeyType is returned when a parsed key is not of the requested type
+var ErrUnexpectedKeyType = errors.New("key is not of the expected type")
+
+// Parse parses a PEM encoded private key and returns it as T,
+// e.g. Parse[*rsa.PrivateKey](data).
+// PKCS1, SEC1 and PKCS8 encodings are supported.
+func Parse[T PrivateKey](data []byte) (T, error) {
+	var zero T
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return zero, errParse
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case privateType:
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			// RSA PRIVATE KEY blocks of this package may hold PKCS8
+			key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		}
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return zero, fmt.Errorf("%w: PEM block %q", errParse, block.Type)
+	}
+	if err != nil {
+		return zero, errParse
+	}
+
+	return asKey[T](key)
+}
+
+// ParsePublic parses a PEM encoded public key and returns it as T,
+// e.g. ParsePublic[ed25519.PublicKey](data).
+// PKCS1 and PKIX encodings are supported.
+func ParsePublic[T PublicKey](data []byte) (T, error) {
+	var zero T
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return zero, errParse
+	}
+
+	var (
+		key any
+		err error
+	)
+	switch block.Type {
+	case publicType:
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		}
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return zero, fmt.Errorf("%w: PEM block %q", errParse, block.Type)
+	}
+	if err != nil {
+		return zero, errParse
+	}
+
+	return asKey[T](key)
+}
+
+func asKey[T any](key any) (T, error) {
+	typed, ok := key.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: got %T, want %T", ErrUnexpectedKeyType, key, zero)
+	}
+
+	return typed, nil
+}
@@ -0,0 +1,197 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ParseAny rejects larger inputs, no key or certificate encoding comes close
+const maxParseAnySize = 64 * 1024
+
+var errNoKey = errors.New("no key found")
+
+// ParsedKey is the result of ParseAny, Private is nil for public keys and certificates
+type ParsedKey struct {
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+	// Format names the encoding that succeeded, e.g. 'PEM PKCS8' or 'DER PKIX'
+	Format string
+}
+
+// Diagnostic records a single parse attempt of ParseAny
+type Diagnostic struct {
+	Attempt string
+	Err     error
+}
+
+// String formats the attempt and its outcome
+func (d Diagnostic) String() string {
+	if d.Err == nil {
+		return d.Attempt + ": ok"
+	}
+
+	return fmt.Sprintf("%s: %v", d.Attempt, d.Err)
+}
+
+type parseAttempt struct {
+	name  string
+	parse func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error)
+}
+
+var derAttempts = []parseAttempt{
+	{"PKCS1 private", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		return privateOnly(x509.ParsePKCS1PrivateKey(der))
+	}},
+	{"PKCS8", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		return privateOnly(x509.ParsePKCS8PrivateKey(der))
+	}},
+	{"SEC1", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		return privateOnly(x509.ParseECPrivateKey(der))
+	}},
+	{"PKCS1 public", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		return publicOnly(x509.ParsePKCS1PublicKey(der))
+	}},
+	{"PKIX", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		return publicOnly(x509.ParsePKIXPublicKey(der))
+	}},
+	{"certificate", func(der []byte) (crypto.PrivateKey, crypto.PublicKey, error) {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, cert.PublicKey, nil
+	}},
+}
+
+// ParseAny tries every known key encoding on data: PEM blocks, DER, base64 DER, OpenSSH keys,
+// authorized_keys lines, and JWKs. It returns the first key found, or nil, together with a diagnostic
+// for every attempt made. Malformed input must never panic, FuzzParseAny guards this.
+func ParseAny(data []byte) (key *ParsedKey, diags []Diagnostic) {
+	if len(data) > maxParseAnySize {
+		return nil, []Diagnostic{{Attempt: "input", Err: fmt.Errorf("%w: input exceeds %d bytes", errParse, maxParseAnySize)}}
+	}
+
+	steps := []func([]byte, *[]Diagnostic) *ParsedKey{parseAnyPEM, parseAnyDER, parseAnyBase64, parseAnySSH, parseAnyJWK}
+	for _, step := range steps {
+		if key = step(data, &diags); key != nil {
+			return key, diags
+		}
+	}
+	diags = append(diags, Diagnostic{Attempt: "result", Err: errNoKey})
+
+	return nil, diags
+}
+
+func parseAnyPEM(data []byte, diags *[]Diagnostic) *ParsedKey {
+	rest := data
+	for i := 1; ; i++ {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			if i == 1 {
+				*diags = append(*diags, Diagnostic{Attempt: "PEM", Err: errors.New("no PEM block")})
+			}
+			return nil
+		}
+
+		prefix := fmt.Sprintf("PEM block %d (%s)", i, block.Type)
+		if block.Headers["Proc-Type"] == legacyProcType {
+			*diags = append(*diags, Diagnostic{Attempt: prefix, Err: errors.New("encrypted, use ReadLegacyEncryptedPrivate")})
+			continue
+		}
+		if key := tryDER(prefix, "PEM", block.Bytes, diags); key != nil {
+			return key
+		}
+	}
+}
+
+func parseAnyDER(data []byte, diags *[]Diagnostic) *ParsedKey {
+	return tryDER("DER", "DER", data, diags)
+}
+
+func parseAnyBase64(data []byte, diags *[]Diagnostic) *ParsedKey {
+	der, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(data), nil)))
+	if err != nil {
+		*diags = append(*diags, Diagnostic{Attempt: "base64", Err: err})
+		return nil
+	}
+
+	return tryDER("base64 DER", "base64 DER", der, diags)
+}
+
+func parseAnySSH(data []byte, diags *[]Diagnostic) *ParsedKey {
+	raw, err := ssh.ParseRawPrivateKey(data)
+	*diags = append(*diags, Diagnostic{Attempt: "OpenSSH private", Err: err})
+	if err == nil {
+		return privateResult(raw, "OpenSSH")
+	}
+
+	sshKey, _, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err == nil {
+		if ck, ok := sshKey.(ssh.CryptoPublicKey); ok {
+			*diags = append(*diags, Diagnostic{Attempt: "authorized_keys"})
+			return &ParsedKey{Public: ck.CryptoPublicKey(), Format: "authorized_keys"}
+		}
+		err = errSSHKeyType
+	}
+	*diags = append(*diags, Diagnostic{Attempt: "authorized_keys", Err: err})
+
+	return nil
+}
+
+func parseAnyJWK(data []byte, diags *[]Diagnostic) *ParsedKey {
+	pub, err := parseJWK(data)
+	*diags = append(*diags, Diagnostic{Attempt: "JWK", Err: err})
+	if err != nil {
+		return nil
+	}
+
+	return &ParsedKey{Public: pub, Format: "JWK"}
+}
+
+func tryDER(prefix, format string, der []byte, diags *[]Diagnostic) *ParsedKey {
+	for _, a := range derAttempts {
+		priv, pub, err := a.parse(der)
+		*diags = append(*diags, Diagnostic{Attempt: prefix + " as " + a.name, Err: err})
+		if err == nil {
+			return &ParsedKey{Private: priv, Public: pub, Format: format + " " + a.name}
+		}
+	}
+
+	return nil
+}
+
+func privateOnly[T any](key T, err error) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed := privateResult(key, "")
+	if parsed == nil {
+		return nil, nil, errParse
+	}
+
+	return parsed.Private, parsed.Public, nil
+}
+
+func publicOnly[T any](key T, err error) (crypto.PrivateKey, crypto.PublicKey, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, crypto.PublicKey(key), nil
+}
+
+func privateResult(key any, format string) *ParsedKey {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil
+	}
+
+	return &ParsedKey{Private: key, Public: signer.Public(), Format: format}
+}
@@ -0,0 +1,70 @@
+package rsakys
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// paths reaching MAX_PATH (260) including the terminating NUL need the extended-length prefix
+// on Windows, directories are limited to 248 characters
+const maxShortPath = 248
+
+// keyFilePath returns the path of '<keyname>.<suffix>' inside dir
+func keyFilePath(dir, keyname, suffix string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s.%s", keyname, suffix))
+}
+
+// hasSuffix reports whether name ends with '.<suffix>', ignoring case on case-insensitive platforms
+func hasSuffix(name, suffix string) bool {
+	return hasSuffixFold(name, suffix, caseInsensitivePaths)
+}
+
+func hasSuffixFold(name, suffix string, fold bool) bool {
+	suffix = "." + suffix
+	if len(name) < len(suffix) {
+		return false
+	}
+	if fold {
+		return strings.EqualFold(name[len(name)-len(suffix):], suffix)
+	}
+
+	return name[len(name)-len(suffix):] == suffix
+}
+
+// trimSuffix removes '.<suffix>' from name if hasSuffix matches
+func trimSuffix(name, suffix string) string {
+	if !hasSuffix(name, suffix) {
+		return name
+	}
+
+	return name[:len(name)-len(suffix)-1]
+}
+
+// matchName reports whether name matches the shell pattern, ignoring case on case-insensitive platforms
+func matchName(pattern, name string) (bool, error) {
+	return matchNameFold(pattern, name, caseInsensitivePaths)
+}
+
+func matchNameFold(pattern, name string, fold bool) (bool, error) {
+	if fold {
+		pattern, name = strings.ToLower(pattern), strings.ToLower(name)
+	}
+
+	return filepath.Match(pattern, name)
+}
+
+// extendedLengthPath adds the Windows extended-length prefix '\\?\' to abs, the absolute form of p,
+// if it reaches maxShortPath. Short paths are returned as p.
+func extendedLengthPath(p, abs string) string {
+	if p == "" || strings.HasPrefix(p, `\\?\`) || len(abs) < maxShortPath {
+		return p
+	}
+
+	if strings.HasPrefix(abs, `\\`) {
+		// UNC paths: \\server\share\... becomes \\?\UNC\server\share\...
+		return `\\?\UNC\` + abs[2:]
+	}
+
+	return `\\?\` + abs
+}
@@ -0,0 +1,14 @@
+//go:build !windows
+
+package rsakys
+
+const (
+	caseInsensitivePaths = false
+	// executableBits reports whether the file system records executable permissions
+	executableBits = true
+)
+
+// fixPath is a no-op outside of Windows
+func fixPath(p string) string {
+	return p
+}
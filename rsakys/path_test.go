@@ -0,0 +1,125 @@
+package rsakys
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestKeyFilePath(t *testing.T) {
+	tests := []struct {
+		dir, keyname, suffix string
+		want                 string
+	}{
+		{"keys", "id_rsa", privateSuffix, filepath.Join("keys", "id_rsa.pem")},
+		{"keys", "id_rsa", publicSuffix, filepath.Join("keys", "id_rsa.pub")},
+		{"", "id_rsa", publicSuffix, "id_rsa.pub"},
+		{filepath.Join("a", "b"), "service.v2", privateSuffix, filepath.Join("a", "b", "service.v2.pem")},
+	}
+
+	for _, tt := range tests {
+		if got := keyFilePath(tt.dir, tt.keyname, tt.suffix); got != tt.want {
+			t.Errorf("keyFilePath(%q, %q, %q) = %q, want %q", tt.dir, tt.keyname, tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestSuffix(t *testing.T) {
+	// suffixes only match regardless of case where the filesystem ignores it
+	upperTrimmed := "id_rsa.PEM"
+	if caseInsensitivePaths {
+		upperTrimmed = "id_rsa"
+	}
+
+	tests := []struct {
+		name, suffix string
+		has          bool
+		trimmed      string
+	}{
+		{"id_rsa.pem", privateSuffix, true, "id_rsa"},
+		{"id_rsa.pub", privateSuffix, false, "id_rsa.pub"},
+		{"id_rsa.PEM", privateSuffix, caseInsensitivePaths, upperTrimmed},
+		{"pem", privateSuffix, false, "pem"},
+		{".pem", privateSuffix, true, ""},
+		{"id_rsapem", privateSuffix, false, "id_rsapem"},
+		{"", privateSuffix, false, ""},
+	}
+
+	for _, tt := range tests {
+		if got := hasSuffix(tt.name, tt.suffix); got != tt.has {
+			t.Errorf("hasSuffix(%q, %q) = %v, want %v", tt.name, tt.suffix, got, tt.has)
+		}
+		if got := trimSuffix(tt.name, tt.suffix); got != tt.trimmed {
+			t.Errorf("trimSuffix(%q, %q) = %q, want %q", tt.name, tt.suffix, got, tt.trimmed)
+		}
+	}
+}
+
+func TestSuffixFold(t *testing.T) {
+	tests := []struct {
+		name string
+		fold bool
+		has  bool
+	}{
+		{"KEY.PEM", true, true},
+		{"KEY.PEM", false, false},
+		{"key.Pem", true, true},
+		{"key.pem", false, true},
+	}
+
+	for _, tt := range tests {
+		if got := hasSuffixFold(tt.name, privateSuffix, tt.fold); got != tt.has {
+			t.Errorf("hasSuffixFold(%q, %v) = %v, want %v", tt.name, tt.fold, got, tt.has)
+		}
+	}
+}
+
+func TestMatchNameFold(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		fold          bool
+		want          bool
+	}{
+		{"*.pem", "KEY.PEM", true, true},
+		{"*.pem", "KEY.PEM", false, false},
+		{"id_*.pub", "ID_RSA.PUB", true, true},
+		{"id_*.pub", "id_rsa.pub", false, true},
+		{"*.pub", "id_rsa.pem", true, false},
+	}
+
+	for _, tt := range tests {
+		got, err := matchNameFold(tt.pattern, tt.name, tt.fold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("matchNameFold(%q, %q, %v) = %v, want %v", tt.pattern, tt.name, tt.fold, got, tt.want)
+		}
+	}
+}
+
+func TestExtendedLengthPath(t *testing.T) {
+	long := strings.Repeat("k", maxShortPath)
+
+	tests := []struct {
+		name, path, abs, want string
+	}{
+		{"empty", "", "", ""},
+		{"short absolute", `C:\keys\a.pem`, `C:\keys\a.pem`, `C:\keys\a.pem`},
+		{"short relative", `keys\a.pem`, `C:\work\keys\a.pem`, `keys\a.pem`},
+		{"short drive-relative", `C:keys\a.pem`, `C:\work\keys\a.pem`, `C:keys\a.pem`},
+		{"already prefixed", `\\?\C:\` + long, `\\?\C:\` + long, `\\?\C:\` + long},
+		{"long absolute", `C:\` + long + `\a.pem`, `C:\` + long + `\a.pem`, `\\?\C:\` + long + `\a.pem`},
+		{"long relative", long + `\a.pem`, `C:\work\` + long + `\a.pem`, `\\?\C:\work\` + long + `\a.pem`},
+		{"long drive-relative", `C:` + long, `C:\work\` + long, `\\?\C:\work\` + long},
+		{"long UNC", `\\server\share\` + long, `\\server\share\` + long, `\\?\UNC\server\share\` + long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extendedLengthPath(tt.path, tt.abs); got != tt.want {
+				t.Errorf("extendedLengthPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package rsakys
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+const (
+	caseInsensitivePaths = true
+	// executableBits reports whether the file system records executable permissions
+	executableBits = false
+)
+
+// fixPath adds the extended-length prefix '\\?\' to long paths, so keys nested deep in a
+// directory tree stay reachable. As the prefix disables the resolution of relative and drive-relative
+// paths like 'C:keys\a.pem', long paths are made absolute first. Short paths are returned unchanged.
+func fixPath(p string) string {
+	if p == "" || strings.HasPrefix(p, `\\?\`) {
+		return p
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+
+	return extendedLengthPath(p, abs)
+}
@@ -0,0 +1,39 @@
+//go:build windows
+
+package rsakys
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixPath(t *testing.T) {
+	long := strings.Repeat("k", maxShortPath)
+	cwd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, path, want string
+	}{
+		{"empty", "", ""},
+		{"short absolute", `C:\keys\a.pem`, `C:\keys\a.pem`},
+		{"short relative", `keys\a.pem`, `keys\a.pem`},
+		{"short drive-relative", `C:keys\a.pem`, `C:keys\a.pem`},
+		{"already prefixed", `\\?\C:\` + long, `\\?\C:\` + long},
+		{"long absolute", `C:\` + long + `\a.pem`, `\\?\C:\` + long + `\a.pem`},
+		{"long relative", long + `\a.pem`, `\\?\` + filepath.Join(cwd, long, "a.pem")},
+		{"long with dot segments", `C:\x\..\` + long, `\\?\C:\` + long},
+		{"long UNC", `\\server\share\` + long, `\\?\UNC\server\share\` + long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fixPath(tt.path); got != tt.want {
+				t.Errorf("fixPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
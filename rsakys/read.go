@@ -6,16 +6,21 @@ import (
 	"encoding/pem"
 	"io"
 	"os"
+	"strings"
 )
 
 func readFile(p string) ([]byte, error) {
-	f, err := os.Open(p)
+	f, err := os.Open(fixPath(p))
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	cntnt, err := io.ReadAll(io.LimitReader(f, tenKB))
+	return readLimited(f)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	cntnt, err := io.ReadAll(io.LimitReader(r, tenKB))
 	if err != nil {
 		return nil, err
 	}
@@ -28,16 +33,31 @@ func readPrivate(p string) (*rsa.PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	return parsePrivate(key)
+}
+
+func parsePrivate(key []byte) (*rsa.PrivateKey, error) {
+	privateKey, _, err := parsePrivateBlock(key)
+
+	return privateKey, err
+}
+
+func parsePrivateBlock(key []byte) (*rsa.PrivateKey, map[string]string, error) {
 	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, nil, errParse
+	}
 
 	if block.Type != privateType {
-		return nil, errWrongPrivateType
+		return nil, nil, errWrongPrivateType
 	}
 
 	var parsedKey interface{}
+	var err error
 	if parsedKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
 		if parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil { // note this returns type `interface{}`
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -45,10 +65,16 @@ func readPrivate(p string) (*rsa.PrivateKey, error) {
 	var ok bool
 	privateKey, ok = parsedKey.(*rsa.PrivateKey)
 	if !ok {
-		return nil, errParse
+		return nil, nil, errParse
 	}
 
-	return privateKey, nil
+	if currentConfig().Strict {
+		if err := Lint(privateKey).Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return privateKey, block.Headers, nil
 }
 
 func readPublic(p string) (*rsa.PublicKey, error) {
@@ -56,16 +82,31 @@ func readPublic(p string) (*rsa.PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	return parsePublic(key)
+}
+
+func parsePublic(key []byte) (*rsa.PublicKey, error) {
+	publicKey, _, err := parsePublicBlock(key)
+
+	return publicKey, err
+}
+
+func parsePublicBlock(key []byte) (*rsa.PublicKey, map[string]string, error) {
 	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, nil, errParse
+	}
 
 	if block.Type != publicType {
-		return nil, errWrongPublicType
+		return nil, nil, errWrongPublicType
 	}
 
 	var parsedKey interface{}
+	var err error
 	if parsedKey, err = x509.ParsePKCS1PublicKey(block.Bytes); err != nil {
 		if parsedKey, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -73,8 +114,26 @@ func readPublic(p string) (*rsa.PublicKey, error) {
 	var ok bool
 	publicKey, ok = parsedKey.(*rsa.PublicKey)
 	if !ok {
-		return nil, errParse
+		return nil, nil, errParse
+	}
+
+	if currentConfig().Strict {
+		if err := LintPublic(publicKey).Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return publicKey, block.Headers, nil
+}
+
+// parseComment returns the first non-empty line following the PEM block
+func parseComment(key []byte) string {
+	_, rest := pem.Decode(key)
+	for _, line := range strings.Split(string(rest), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
 	}
 
-	return publicKey, nil
+	return ""
 }
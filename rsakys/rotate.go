@@ -0,0 +1,246 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	errNoVersion  = errors.New("no matching key version found")
+	errNotSymlink = errors.New("key file is not a symlink, rotate it with RotateKeypair first")
+)
+
+// RotateKeypair generates a new keypair, writes it as '<keyname>-v<N>.pem' in the configured private format
+// and as PKIX '<keyname>-v<N>.pub' PEM file with N being the next free version,
+// points the '<keyname>.pem' and '<keyname>.pub' symlinks at it, and returns the RSA private key struct together with its version.
+// An existing unversioned '<keyname>.pem' and '<keyname>.pub' are kept as the version before the new one.
+func RotateKeypair(path, keyname string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, int, error) {
+	if err := versionExistingKeypair(path, keyname); err != nil {
+		return nil, 0, err
+	}
+
+	versions, err := KeyVersions(path, keyname)
+	if err != nil {
+		return nil, 0, err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+
+	privateKey, err := generateKey(bitSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = writePrivateKey(versionedPath(path, keyname, version, privateSuffix), privateKey, currentConfig().PrivateFormat, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = writePublicKey(versionedPath(path, keyname, version, publicSuffix), &privateKey.PublicKey, PKIX, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := ActivateKeyVersion(path, keyname, version); err != nil {
+		return nil, 0, err
+	}
+
+	return privateKey, version, nil
+}
+
+// ActivateKeyVersion points the '<keyname>.pem' and '<keyname>.pub' symlinks at the given version.
+// The links are swapped one after the other: '.pub' first and '.pem' last, so ActiveKeyVersion only
+// reports the new version once both links point at it. A reader opening both links during the swap
+// may still see the new public key next to the previous private key.
+func ActivateKeyVersion(path, keyname string, version int) error {
+	privTarget := versionedPath(path, keyname, version, privateSuffix)
+	pubTarget := versionedPath(path, keyname, version, publicSuffix)
+	for _, target := range []string{privTarget, pubTarget} {
+		if _, err := os.Stat(target); err != nil {
+			return err
+		}
+	}
+
+	pubLink := keyFilePath(path, keyname, publicSuffix)
+	privLink := keyFilePath(path, keyname, privateSuffix)
+	for _, link := range []string{pubLink, privLink} {
+		if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			return fmt.Errorf("%w: %s", errNotSymlink, link)
+		}
+	}
+
+	prevPub, prevErr := os.Readlink(pubLink)
+	if err := replaceSymlink(filepath.Base(pubTarget), pubLink); err != nil {
+		return err
+	}
+	if err := replaceSymlink(filepath.Base(privTarget), privLink); err != nil {
+		// keep the links consistent with the still active private key
+		if prevErr == nil {
+			_ = replaceSymlink(prevPub, pubLink)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// versionExistingKeypair moves a regular '<keyname>.pem' and '<keyname>.pub' to the next free version,
+// so switching a keypair to rotation never replaces the only copy of a key by a symlink
+func versionExistingKeypair(path, keyname string) error {
+	privLink := keyFilePath(path, keyname, privateSuffix)
+	info, err := os.Lstat(privLink)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.Mode()&os.ModeSymlink != 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	versions, err := KeyVersions(path, keyname)
+	if err != nil {
+		return err
+	}
+	version := 1
+	if len(versions) > 0 {
+		version = versions[len(versions)-1] + 1
+	}
+
+	pubLink := keyFilePath(path, keyname, publicSuffix)
+	if info, err := os.Lstat(pubLink); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		if err := os.Rename(pubLink, versionedPath(path, keyname, version, publicSuffix)); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(privLink, versionedPath(path, keyname, version, privateSuffix))
+}
+
+// ActiveKeyVersion returns the version the '<keyname>.pem' symlink currently points at
+func ActiveKeyVersion(path, keyname string) (int, error) {
+	target, err := os.Readlink(keyFilePath(path, keyname, privateSuffix))
+	if err != nil {
+		return 0, err
+	}
+
+	m := versionPattern(keyname, privateSuffix).FindStringSubmatch(filepath.Base(target))
+	if m == nil {
+		return 0, errNoVersion
+	}
+
+	return strconv.Atoi(m[1])
+}
+
+// RollbackKeyVersion points the symlinks at the highest version below the active one
+// and returns that version
+func RollbackKeyVersion(path, keyname string) (int, error) {
+	active, err := ActiveKeyVersion(path, keyname)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := KeyVersions(path, keyname)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i] < active {
+			return versions[i], ActivateKeyVersion(path, keyname, versions[i])
+		}
+	}
+
+	return 0, errNoVersion
+}
+
+// AdvanceKeyVersion points the symlinks at the lowest version above the active one
+// and returns that version
+func AdvanceKeyVersion(path, keyname string) (int, error) {
+	active, err := ActiveKeyVersion(path, keyname)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := KeyVersions(path, keyname)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, v := range versions {
+		if v > active {
+			return v, ActivateKeyVersion(path, keyname, v)
+		}
+	}
+
+	return 0, errNoVersion
+}
+
+// KeyVersions returns the ascending versions of '<keyname>-v<N>.pem' files found in path
+func KeyVersions(path, keyname string) ([]int, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	re := versionPattern(keyname, privateSuffix)
+	var versions []int
+	for _, e := range entries {
+		m := re.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		v, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+
+	return versions, nil
+}
+
+func versionedPath(path, keyname string, version int, suffix string) string {
+	return filepath.Join(path, fmt.Sprintf("%s-v%d.%s", keyname, version, suffix))
+}
+
+func versionPattern(keyname, suffix string) *regexp.Regexp {
+	flags := ""
+	if caseInsensitivePaths {
+		flags = "(?i)"
+	}
+
+	return regexp.MustCompile(fmt.Sprintf(`%s^%s-v([0-9]+)\.%s$`, flags, regexp.QuoteMeta(keyname), suffix))
+}
+
+// replaceSymlink atomically replaces link by creating a uniquely named temporary symlink and renaming it,
+// so concurrent activations never share a temporary name
+func replaceSymlink(target, link string) error {
+	var tmp string
+	for i := 0; ; i++ {
+		suffix, err := randomSuffix()
+		if err != nil {
+			return err
+		}
+		tmp = filepath.Join(filepath.Dir(link), "."+filepath.Base(link)+".tmp-"+suffix)
+
+		err = os.Symlink(target, tmp)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) || i == 9 {
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
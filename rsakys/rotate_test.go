@@ -0,0 +1,70 @@
+package rsakys
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRotateKeypairVersionsExistingKeypair(t *testing.T) {
+	dir := t.TempDir()
+	original, err := GeneratePKCS8Keypair(dir, "k", Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated, version, err := RotateKeypair(dir, "k", Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 2 {
+		t.Fatalf("got version %d, want 2", version)
+	}
+
+	kept, err := ReadPrivate(versionedPath(dir, "k", 1, privateSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(kept, original) {
+		t.Fatal("the existing key was not kept as version 1")
+	}
+	active, err := ReadPrivate(keyFilePath(dir, "k", privateSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(active, rotated) {
+		t.Fatal("the link does not point at the rotated key")
+	}
+
+	if v, err := RollbackKeyVersion(dir, "k"); err != nil || v != 1 {
+		t.Fatalf("rollback returned %d, %v", v, err)
+	}
+	pub, err := ReadPublic(keyFilePath(dir, "k", publicSuffix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !EqualPublic(pub, &original.PublicKey) {
+		t.Fatal("the public link does not point at version 1")
+	}
+}
+
+func TestActivateKeyVersionRefusesRegularFile(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := RotateKeypair(dir, "k", Bits2048); err != nil {
+		t.Fatal(err)
+	}
+	link := keyFilePath(dir, "k", privateSuffix)
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(link, []byte("only copy"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ActivateKeyVersion(dir, "k", 1); !errors.Is(err, errNotSymlink) {
+		t.Fatalf("got %v, want %v", err, errNotSymlink)
+	}
+	if cntnt, err := os.ReadFile(link); err != nil || string(cntnt) != "only copy" {
+		t.Fatal("the regular file was replaced")
+	}
+}
@@ -1,13 +1,15 @@
 package rsakys
 
 import (
-	"crypto/rand"
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 )
 
-type pemFormat uint
+// Format is the encoding of a key inside its PEM block
+type Format uint
 
 const (
 	privateType         = "RSA PRIVATE KEY"
@@ -17,12 +19,27 @@ const (
 	tenKB         int64 = 10 * 1024
 )
 
+// Supported key encodings, the zero Format is unset and selects a default where one applies
 const (
-	pkcs1 pemFormat = iota
-	pkcs8
-	pkix
+	PKCS1 Format = iota + 1
+	PKCS8
+	PKIX
 )
 
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case PKCS1:
+		return "PKCS1"
+	case PKCS8:
+		return "PKCS8"
+	case PKIX:
+		return "PKIX"
+	default:
+		return fmt.Sprintf("Format(%d)", uint(f))
+	}
+}
+
 var (
 	errWrongPrivateType = errors.New("key is not of type RSA PRIVATE KEY")
 	errWrongPublicType  = errors.New("key is not of type RSA PUBLIC KEY")
@@ -39,97 +56,220 @@ func ReadPublic(path string) (*rsa.PublicKey, error) {
 	return readPublic(path)
 }
 
-// ReadPrivatePKCS1 reads a private key PEM file and returns a PKCS1 encoded private key byte slice
+// ReadPrivateFrom reads a private key PEM block from an already open file,
+// e.g. of a virtual filesystem or a sandboxed file handle, and returns the private key struct
+func ReadPrivateFrom(file fs.File) (*rsa.PrivateKey, error) {
+	key, err := readLimited(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivate(key)
+}
+
+// ReadPublicFrom reads a public key PEM block from an already open file,
+// e.g. of a virtual filesystem or a sandboxed file handle, and returns the public key struct
+func ReadPublicFrom(file fs.File) (*rsa.PublicKey, error) {
+	key, err := readLimited(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePublic(key)
+}
+
+// ReadPrivateAt reads a private key PEM block of the given size from r, e.g. an archive member,
+// and returns the private key struct
+func ReadPrivateAt(r io.ReaderAt, size int64) (*rsa.PrivateKey, error) {
+	key, err := readLimited(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivate(key)
+}
+
+// ReadPublicAt reads a public key PEM block of the given size from r, e.g. an archive member,
+// and returns the public key struct
+func ReadPublicAt(r io.ReaderAt, size int64) (*rsa.PublicKey, error) {
+	key, err := readLimited(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePublic(key)
+}
+
+// ReadPrivateFS reads a private key PEM file from a filesystem, e.g. an embed.FS, and returns the private key struct
+func ReadPrivateFS(fsys fs.FS, name string) (*rsa.PrivateKey, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadPrivateFrom(file)
+}
+
+// ReadPublicFS reads a public key PEM file from a filesystem, e.g. an embed.FS, and returns the public key struct
+func ReadPublicFS(fsys fs.FS, name string) (*rsa.PublicKey, error) {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadPublicFrom(file)
+}
+
+// ReadPrivateWithHeaders reads a private key PEM file and returns the private key struct
+// together with the headers of its PEM block
+func ReadPrivateWithHeaders(path string) (*rsa.PrivateKey, map[string]string, error) {
+	key, err := readFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsePrivateBlock(key)
+}
+
+// ReadPublicWithHeaders reads a public key PEM file and returns the public key struct
+// together with the headers of its PEM block
+func ReadPublicWithHeaders(path string) (*rsa.PublicKey, map[string]string, error) {
+	key, err := readFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return parsePublicBlock(key)
+}
+
+// ReadPublicWithComment reads a public key PEM file and returns the public key struct
+// together with the comment line following its PEM block, if any
+func ReadPublicWithComment(path string) (*rsa.PublicKey, string, error) {
+	key, err := readFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	publicKey, err := parsePublic(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return publicKey, parseComment(key), nil
+}
+
+// ReadPrivatePKCS1 reads a private key PEM file and returns a PKCS1 encoded private key byte slice.
+// Like the other Read<Format> functions it returns the stored block without parsing the key,
+// if it already has the requested encoding.
 func ReadPrivatePKCS1(path string) ([]byte, error) {
+	if encoded, ok := readEncoded(path, privateType, isPKCS1PrivateKey); ok {
+		return encoded, nil
+	}
+
 	key, err := readPrivate(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return encodePrivateKey(key, pkcs1)
+	return encodePrivateKey(key, PKCS1)
 }
 
 // ReadPrivatePKCS8  reads a private key PEM file and returns a PKCS8 encoded private key byte slice
 func ReadPrivatePKCS8(path string) ([]byte, error) {
+	if encoded, ok := readEncoded(path, privateType, isPKCS8PrivateKey); ok {
+		return encoded, nil
+	}
+
 	key, err := readPrivate(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return encodePrivateKey(key, pkcs8)
+	return encodePrivateKey(key, PKCS8)
 }
 
 // ReadPublicPKCS1 reads a public key PEM file and returns a PKCS1 encoded public key byte slice
 func ReadPublicPKCS1(path string) ([]byte, error) {
+	if encoded, ok := readEncoded(path, publicType, isPKCS1PublicKey); ok {
+		return encoded, nil
+	}
+
 	key, err := readPublic(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return encodePublicKey(key, pkcs1)
+	return encodePublicKey(key, PKCS1)
 }
 
 // ReadPublicPKIX reads a public key PEM file and returns a PKIX encoded public key byte slice
 func ReadPublicPKIX(path string) ([]byte, error) {
+	if encoded, ok := readEncoded(path, publicType, isPKIXPublicKey); ok {
+		return encoded, nil
+	}
+
 	key, err := readPublic(path)
 	if err != nil {
 		return nil, err
 	}
 
-	return encodePublicKey(key, pkix)
+	return encodePublicKey(key, PKIX)
 }
 
-// GetPrivateKey generates an RSA private key struct of the given bit size
+// GetPrivateKey generates an RSA private key struct of the given bit size.
+// All generation functions reject sizes below MinBits and use DefaultBits for a bit size of 0.
 func GetPrivateKey(bitSize int) (*rsa.PrivateKey, error) {
-	return rsa.GenerateKey(rand.Reader, bitSize)
+	return generateKey(bitSize)
 }
 
 // GetPKCS1PrivateKey generates an RSA private key and returns the PKCS1 byterepresentation of the PEM block
 func GetPKCS1PrivateKey(bitSize int) ([]byte, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
-	return encodePrivateKey(privateKey, pkcs1)
+	return encodePrivateKey(privateKey, PKCS1)
 }
 
 // GetPKCS8PrivateKey generates an RSA private key and returns the PKCS8 byterepresentation of the PEM block
 func GetPKCS8PrivateKey(bitSize int) ([]byte, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
-	return encodePrivateKey(privateKey, pkcs8)
+	return encodePrivateKey(privateKey, PKCS8)
 }
 
 // GetPKCS1PrivateKeyString returns the PKCS1 byterepresentation of a given RSA private key struct
-func GetPKCS1PrivateKeyString(privateKey *rsa.PrivateKey) ([]byte, error) {
-	return encodePrivateKey(privateKey, pkcs1)
+func GetPKCS1PrivateKeyString(privateKey *rsa.PrivateKey, opts ...WriteOption) ([]byte, error) {
+	return encodePrivateKey(privateKey, PKCS1, opts...)
 }
 
 // GetPKCS8PrivateKeyString returns the PKCS8 byterepresentation of a given RSA private key struct
-func GetPKCS8PrivateKeyString(privateKey *rsa.PrivateKey) ([]byte, error) {
-	return encodePrivateKey(privateKey, pkcs8)
+func GetPKCS8PrivateKeyString(privateKey *rsa.PrivateKey, opts ...WriteOption) ([]byte, error) {
+	return encodePrivateKey(privateKey, PKCS8, opts...)
 }
 
 // GetPKCS1PublicKeyString returns the PKCS1 byterepresentation of a given RSA public key struct
-func GetPKCS1PublicKeyString(publicKey *rsa.PublicKey) ([]byte, error) {
-	return encodePublicKey(publicKey, pkcs1)
+func GetPKCS1PublicKeyString(publicKey *rsa.PublicKey, opts ...WriteOption) ([]byte, error) {
+	return encodePublicKey(publicKey, PKCS1, opts...)
 }
 
 // GetPKIXPublicKeyString returns the PKIX byterepresentation of a given RSA public key struct
-func GetPKIXPublicKeyString(publicKey *rsa.PublicKey) ([]byte, error) {
-	return encodePublicKey(publicKey, pkix)
+func GetPKIXPublicKeyString(publicKey *rsa.PublicKey, opts ...WriteOption) ([]byte, error) {
+	return encodePublicKey(publicKey, PKIX, opts...)
 }
 
 // GeneratePKCS1PrivateKey generates a new private key of the given bit size,
 // writes it as PKCS1 PEM file to disc, and returns the RSA private key struct
-func GeneratePKCS1PrivateKey(path string, bitSize int) (*rsa.PrivateKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+func GeneratePKCS1PrivateKey(path string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, error) {
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
-	err = writePrivateKey(path, privateKey, pkcs1)
+	err = writePrivateKey(path, privateKey, PKCS1, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -139,12 +279,12 @@ func GeneratePKCS1PrivateKey(path string, bitSize int) (*rsa.PrivateKey, error)
 
 // GeneratePKCS8PrivateKey generates a new private key of the given bit size,
 // writes it as PKCS8 PEM file to disc, and returns the RSA private key struct
-func GeneratePKCS8PrivateKey(path string, bitSize int) (*rsa.PrivateKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+func GeneratePKCS8PrivateKey(path string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, error) {
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
-	err = writePrivateKey(path, privateKey, pkcs8)
+	err = writePrivateKey(path, privateKey, PKCS8, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -156,24 +296,26 @@ func GeneratePKCS8PrivateKey(path string, bitSize int) (*rsa.PrivateKey, error)
 // writes its private key part with '.pem' suffix as PKCS1 PEM file to disc,
 // writes its public key part with '.pub' suffix as PKIX PEM file to disc,
 // and returns the RSA private key struct
-func GeneratePKCS1Keypair(path, keyname string, bitSize int) (*rsa.PrivateKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+func GeneratePKCS1Keypair(path, keyname string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, error) {
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
 	err = writePrivateKey(
-		fmt.Sprintf("%s/%s.%s", path, keyname, privateSuffix),
+		keyFilePath(path, keyname, privateSuffix),
 		privateKey,
-		pkcs1,
+		PKCS1,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	err = writePublicKey(
-		fmt.Sprintf("%s/%s.%s", path, keyname, publicSuffix),
+		keyFilePath(path, keyname, publicSuffix),
 		&privateKey.PublicKey,
-		pkix,
+		PKIX,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -186,24 +328,26 @@ func GeneratePKCS1Keypair(path, keyname string, bitSize int) (*rsa.PrivateKey, e
 // writes its private key part with '.pem' suffix as PKCS8 PEM file to disc,
 // writes its public key part with '.pub' suffix as PKIX PEM file to disc,
 // and returns the RSA private key struct
-func GeneratePKCS8Keypair(path, keyname string, bitSize int) (*rsa.PrivateKey, error) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, bitSize)
+func GeneratePKCS8Keypair(path, keyname string, bitSize int, opts ...WriteOption) (*rsa.PrivateKey, error) {
+	privateKey, err := generateKey(bitSize)
 	if err != nil {
 		return nil, err
 	}
 	err = writePrivateKey(
-		fmt.Sprintf("%s/%s.%s", path, keyname, privateSuffix),
+		keyFilePath(path, keyname, privateSuffix),
 		privateKey,
-		pkcs8,
+		PKCS8,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	err = writePublicKey(
-		fmt.Sprintf("%s/%s.%s", path, keyname, publicSuffix),
+		keyFilePath(path, keyname, publicSuffix),
 		&privateKey.PublicKey,
-		pkix,
+		PKIX,
+		opts...,
 	)
 	if err != nil {
 		return nil, err
@@ -213,21 +357,21 @@ func GeneratePKCS8Keypair(path, keyname string, bitSize int) (*rsa.PrivateKey, e
 }
 
 // WritePKCS1PrivateKey writes a given RSA private key as PKCS1 PEM block to disc
-func WritePKCS1PrivateKey(privateKey *rsa.PrivateKey, path string) error {
-	return writePrivateKey(path, privateKey, pkcs1)
+func WritePKCS1PrivateKey(privateKey *rsa.PrivateKey, path string, opts ...WriteOption) error {
+	return writePrivateKey(path, privateKey, PKCS1, opts...)
 }
 
 // WritePKCS8PrivateKey writes a given RSA private key as PKCS8 PEM block to disc
-func WritePKCS8PrivateKey(privateKey *rsa.PrivateKey, path string) error {
-	return writePrivateKey(path, privateKey, pkcs8)
+func WritePKCS8PrivateKey(privateKey *rsa.PrivateKey, path string, opts ...WriteOption) error {
+	return writePrivateKey(path, privateKey, PKCS8, opts...)
 }
 
 // WritePKCS1PublicKey writes the public key part of a given RSA private key as PKCS1 PEM block to disc
-func WritePKCS1PublicKey(publicKey *rsa.PublicKey, path string) error {
-	return writePublicKey(path, publicKey, pkcs1)
+func WritePKCS1PublicKey(publicKey *rsa.PublicKey, path string, opts ...WriteOption) error {
+	return writePublicKey(path, publicKey, PKCS1, opts...)
 }
 
 // WritePKIXPublicKey writes the public key part of a given RSA private key as PKIX PEM block to disc
-func WritePKIXPublicKey(publicKey *rsa.PublicKey, path string) error {
-	return writePublicKey(path, publicKey, pkix)
+func WritePKIXPublicKey(publicKey *rsa.PublicKey, path string, opts ...WriteOption) error {
+	return writePublicKey(path, publicKey, PKIX, opts...)
 }
@@ -0,0 +1,200 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sealedValuePrefix = "ENC[rsakys,"
+	sealedValueSuffix = "]"
+)
+
+// SealValue encrypts a single config value for all recipients,
+// the result has the form 'ENC[rsakys,<base64>]' and can be stored in place of the value
+func SealValue(value string, recipients []*rsa.PublicKey, opts ...EncryptOption) (string, error) {
+	var buf bytes.Buffer
+	if err := EncryptStreamWithOptions(&buf, strings.NewReader(value), recipients, opts...); err != nil {
+		return "", err
+	}
+
+	return sealedValuePrefix + base64.StdEncoding.EncodeToString(buf.Bytes()) + sealedValueSuffix, nil
+}
+
+// OpenValue decrypts a value sealed by SealValue, other values are returned unchanged
+func OpenValue(value string, privateKey *rsa.PrivateKey, opts ...EncryptOption) (string, error) {
+	if !IsSealedValue(value) {
+		return value, nil
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(value[len(sealedValuePrefix) : len(value)-len(sealedValueSuffix)])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	var buf bytes.Buffer
+	if err := DecryptStream(&buf, bytes.NewReader(ciphertext), privateKey, opts...); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// IsSealedValue reports whether a value was sealed by SealValue
+func IsSealedValue(value string) bool {
+	return strings.HasPrefix(value, sealedValuePrefix) && strings.HasSuffix(value, sealedValueSuffix)
+}
+
+// EncryptConfig seals the string values of a JSON document whose object keys are listed in keys,
+// or all string values if no keys are given. Already sealed values are kept.
+// The structure and all other values stay readable, object keys are written in sorted order.
+func EncryptConfig(data []byte, recipients []*rsa.PublicKey, keys ...string) ([]byte, error) {
+	return EncryptConfigWithOptions(data, recipients, keys)
+}
+
+// EncryptConfigWithOptions is EncryptConfig with options applied to every sealed value
+func EncryptConfigWithOptions(data []byte, recipients []*rsa.PublicKey, keys []string, opts ...EncryptOption) ([]byte, error) {
+	return transformJSON(data, sealFields(recipients, keys, opts))
+}
+
+// DecryptConfig opens all sealed values of a JSON document, e.g. when the config is loaded,
+// opts must match those the values were sealed with
+func DecryptConfig(data []byte, privateKey *rsa.PrivateKey, opts ...EncryptOption) ([]byte, error) {
+	return transformJSON(data, openFields(privateKey, opts))
+}
+
+// EncryptYAMLConfig seals the string values of a YAML document like EncryptConfig.
+// Key order, comments, and anchors are kept, an alias is sealed where its anchor is defined.
+func EncryptYAMLConfig(data []byte, recipients []*rsa.PublicKey, keys ...string) ([]byte, error) {
+	return EncryptYAMLConfigWithOptions(data, recipients, keys)
+}
+
+// EncryptYAMLConfigWithOptions is EncryptYAMLConfig with options applied to every sealed value
+func EncryptYAMLConfigWithOptions(data []byte, recipients []*rsa.PublicKey, keys []string, opts ...EncryptOption) ([]byte, error) {
+	return transformYAML(data, sealFields(recipients, keys, opts))
+}
+
+// DecryptYAMLConfig opens all sealed values of a YAML document,
+// opts must match those the values were sealed with
+func DecryptYAMLConfig(data []byte, privateKey *rsa.PrivateKey, opts ...EncryptOption) ([]byte, error) {
+	return transformYAML(data, openFields(privateKey, opts))
+}
+
+func sealFields(recipients []*rsa.PublicKey, keys []string, opts []EncryptOption) func(key, value string) (string, error) {
+	fields := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		fields[k] = true
+	}
+
+	return func(key, value string) (string, error) {
+		if IsSealedValue(value) || (len(fields) > 0 && !fields[key]) {
+			return value, nil
+		}
+		return SealValue(value, recipients, opts...)
+	}
+}
+
+func openFields(privateKey *rsa.PrivateKey, opts []EncryptOption) func(key, value string) (string, error) {
+	return func(_, value string) (string, error) {
+		return OpenValue(value, privateKey, opts...)
+	}
+}
+
+func transformJSON(data []byte, fn func(key, value string) (string, error)) ([]byte, error) {
+	// numbers are kept as json.Number, float64 would round large integers
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	doc, err := walkConfig("", doc, fn)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// walkConfig applies fn to all string values, array elements inherit the key of their array
+func walkConfig(key string, node any, fn func(key, value string) (string, error)) (any, error) {
+	var err error
+	switch v := node.(type) {
+	case string:
+		return fn(key, v)
+	case map[string]any:
+		for k, child := range v {
+			if v[k], err = walkConfig(k, child, fn); err != nil {
+				return nil, err
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if v[i], err = walkConfig(key, child, fn); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return node, nil
+}
+
+func transformYAML(data []byte, fn func(key, value string) (string, error)) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if err := walkYAML("", &doc, fn); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// walkYAML applies fn to all string scalars like walkConfig, mapping keys are left untouched
+func walkYAML(key string, node *yaml.Node, fn func(key, value string) (string, error)) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" {
+			return nil
+		}
+		value, err := fn(key, node.Value)
+		if err != nil {
+			return err
+		}
+		if value != node.Value {
+			// the encoder picks a style fitting the new value, quoting it where needed to stay a string
+			node.Value, node.Style = value, 0
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if err := walkYAML(node.Content[i].Value, node.Content[i+1], fn); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := walkYAML(key, child, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
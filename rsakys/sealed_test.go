@@ -0,0 +1,161 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSealValue(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := SealValue("hunter2", []*rsa.PublicKey{&key.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealedValue(sealed) || strings.Contains(sealed, "hunter2") {
+		t.Fatalf("value is not sealed: %s", sealed)
+	}
+
+	if got, err := OpenValue(sealed, key); err != nil || got != "hunter2" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+	if got, err := OpenValue("plain", key); err != nil || got != "plain" {
+		t.Fatalf("plain value changed: %q, %v", got, err)
+	}
+	if _, err := OpenValue(sealed, other); err == nil {
+		t.Fatal("opened a value sealed for another key")
+	}
+	if _, err := OpenValue(sealedValuePrefix+"!!"+sealedValueSuffix, key); !errors.Is(err, ErrMalformed) {
+		t.Fatalf("expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipients := []*rsa.PublicKey{&key.PublicKey}
+
+	jsonDoc := []byte(`{"db": {"user": "app", "password": "hunter2", "port": 5432}, "tokens": ["a", "b"], "id": 12345678901234567890}`)
+	yamlDoc := []byte(`# database settings
+db:
+  user: app
+  password: hunter2 # rotated monthly
+  port: 5432
+tokens:
+  - a
+  - b
+pin: "0042"
+`)
+
+	tests := []struct {
+		name    string
+		doc     []byte
+		encrypt func(data []byte, keys ...string) ([]byte, error)
+		decrypt func(data []byte) ([]byte, error)
+		parse   func(data []byte, v any) error
+	}{
+		{
+			name: "json",
+			doc:  jsonDoc,
+			encrypt: func(data []byte, keys ...string) ([]byte, error) {
+				return EncryptConfig(data, recipients, keys...)
+			},
+			decrypt: func(data []byte) ([]byte, error) { return DecryptConfig(data, key) },
+			parse:   json.Unmarshal,
+		},
+		{
+			name: "yaml",
+			doc:  yamlDoc,
+			encrypt: func(data []byte, keys ...string) ([]byte, error) {
+				return EncryptYAMLConfig(data, recipients, keys...)
+			},
+			decrypt: func(data []byte) ([]byte, error) { return DecryptYAMLConfig(data, key) },
+			parse:   yaml.Unmarshal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := tt.encrypt(tt.doc, "password", "tokens")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(string(sealed), "hunter2") || !strings.Contains(string(sealed), "app") {
+				t.Fatalf("wrong values sealed:\n%s", sealed)
+			}
+
+			// sealing twice keeps the sealed values
+			again, err := tt.encrypt(sealed, "password", "tokens")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(again) != string(sealed) {
+				t.Fatalf("sealed values changed:\n%s\n%s", sealed, again)
+			}
+
+			opened, err := tt.decrypt(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got any
+			if err := tt.parse(tt.doc, &want); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.parse(opened, &got); err != nil {
+				t.Fatal(err)
+			}
+			wantJSON, _ := json.Marshal(want)
+			gotJSON, _ := json.Marshal(got)
+			if string(wantJSON) != string(gotJSON) {
+				t.Fatalf("got %s, want %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestYAMLConfigKeepsLayout(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := []byte(`# settings
+zeta: "1"
+alpha: &secret "007"
+beta: *secret
+`)
+	sealed, err := EncryptYAMLConfig(doc, []*rsa.PublicKey{&key.PublicKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(sealed), "# settings\nzeta: ") || strings.Index(string(sealed), "zeta") > strings.Index(string(sealed), "alpha") {
+		t.Fatalf("comments or key order lost:\n%s", sealed)
+	}
+
+	opened, err := DecryptYAMLConfig(sealed, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := yaml.Unmarshal(opened, &got); err != nil {
+		t.Fatal(err)
+	}
+	// strings looking like numbers stay strings
+	if got["zeta"] != "1" || got["alpha"] != "007" || got["beta"] != "007" {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,210 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+)
+
+const sharePrefix = "RSAKYS-SHARE-1-"
+
+var (
+	errShareParams   = errors.New("shares require 2 <= threshold <= count <= 255")
+	errShareFormat   = errors.New("share is not a valid encoded share")
+	errShareChecksum = errors.New("share checksum mismatch")
+	errShareMismatch = errors.New("shares do not belong to the same split")
+	errShareCount    = errors.New("not enough shares to reach the threshold")
+)
+
+// Share is one part of a private key split with Shamir's secret sharing.
+//
+// The text encoding of a share is 'RSAKYS-SHARE-1-' followed by the unpadded
+// base64url encoding of: threshold (1 byte) | index (1 byte) | share bytes |
+// the first 4 bytes of the SHA-256 digest of everything before.
+// The share bytes are the evaluations over GF(2^8) (AES polynomial 0x11b) at x = index
+// of random polynomials whose constant terms are the bytes of the PKCS8 encoded key.
+type Share struct {
+	Threshold byte
+	Index     byte
+	Data      []byte
+}
+
+// SplitKey splits a given RSA private key into n shares of which any k reconstruct the key
+func SplitKey(privateKey *rsa.PrivateKey, n, k int) ([]Share, error) {
+	if k < 2 || k > n || n > 255 {
+		return nil, errShareParams
+	}
+
+	secret, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{
+			Threshold: byte(k),
+			Index:     byte(i + 1),
+			Data:      make([]byte, len(secret)),
+		}
+	}
+
+	coeffs := make([]byte, k)
+	for pos, b := range secret {
+		if _, err := io.ReadFull(currentConfig().Rand, coeffs[1:]); err != nil {
+			return nil, err
+		}
+		coeffs[0] = b
+
+		for i := range shares {
+			shares[i].Data[pos] = gfEval(coeffs, shares[i].Index)
+		}
+	}
+
+	return shares, nil
+}
+
+// CombineShares reconstructs the RSA private key struct from at least threshold shares
+func CombineShares(shares []Share) (*rsa.PrivateKey, error) {
+	if len(shares) == 0 {
+		return nil, errShareCount
+	}
+
+	threshold := shares[0].Threshold
+	size := len(shares[0].Data)
+	seen := make(map[byte]bool, len(shares))
+	var unique []Share
+	for _, s := range shares {
+		if s.Threshold != threshold || len(s.Data) != size || s.Index == 0 {
+			return nil, errShareMismatch
+		}
+		if seen[s.Index] {
+			continue
+		}
+		seen[s.Index] = true
+		unique = append(unique, s)
+	}
+	if len(unique) < int(threshold) {
+		return nil, errShareCount
+	}
+	unique = unique[:threshold]
+
+	secret := make([]byte, size)
+	for i, si := range unique {
+		basis := byte(1)
+		for j, sj := range unique {
+			if i == j {
+				continue
+			}
+			basis = gfMul(basis, gfDiv(sj.Index, sj.Index^si.Index))
+		}
+		for pos := range secret {
+			secret[pos] ^= gfMul(si.Data[pos], basis)
+		}
+	}
+
+	parsedKey, err := x509.ParsePKCS8PrivateKey(secret)
+	if err != nil {
+		return nil, errShareMismatch
+	}
+	privateKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errParse
+	}
+
+	return privateKey, nil
+}
+
+// String returns the text encoding of the share
+func (s Share) String() string {
+	raw := make([]byte, 0, len(s.Data)+6)
+	raw = append(raw, s.Threshold, s.Index)
+	raw = append(raw, s.Data...)
+	sum := sha256.Sum256(raw)
+	raw = append(raw, sum[:4]...)
+
+	return sharePrefix + base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseShare decodes the text encoding of a share
+func ParseShare(encoded string) (Share, error) {
+	encoded = strings.TrimSpace(encoded)
+	if !strings.HasPrefix(encoded, sharePrefix) {
+		return Share{}, errShareFormat
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(encoded, sharePrefix))
+	if err != nil || len(raw) < 7 {
+		return Share{}, errShareFormat
+	}
+
+	body, checksum := raw[:len(raw)-4], raw[len(raw)-4:]
+	sum := sha256.Sum256(body)
+	if !bytes.Equal(sum[:4], checksum) {
+		return Share{}, errShareChecksum
+	}
+
+	return Share{
+		Threshold: body[0],
+		Index:     body[1],
+		Data:      body[2:],
+	}, nil
+}
+
+var gfExp, gfLog = gfTables()
+
+func gfTables() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// multiply by the generator 3
+		x ^= gfXtime(x)
+	}
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+
+	return exp, log
+}
+
+func gfXtime(b byte) byte {
+	if b&0x80 != 0 {
+		return b<<1 ^ 0x1b
+	}
+
+	return b << 1
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+func gfEval(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coeffs[i]
+	}
+
+	return y
+}
@@ -0,0 +1,155 @@
+package rsakys
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestGFArithmetic(t *testing.T) {
+	// FIPS-197 section 4.2
+	tests := []struct {
+		a, b, want byte
+	}{
+		{0x57, 0x83, 0xc1},
+		{0x57, 0x13, 0xfe},
+		{0x57, 0x02, 0xae},
+		{0x00, 0x13, 0x00},
+		{0x01, 0x13, 0x13},
+	}
+	for _, tt := range tests {
+		if got := gfMul(tt.a, tt.b); got != tt.want {
+			t.Fatalf("%#x * %#x = %#x, want %#x", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	for a := 0; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if got := gfMul(gfDiv(byte(a), byte(b)), byte(b)); got != byte(a) {
+				t.Fatalf("(%#x / %#x) * %#x = %#x", a, b, b, got)
+			}
+		}
+	}
+}
+
+func TestSplitKeyAnySubset(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n, k = 5, 3
+	shares, err := SplitKey(key, n, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subsets := 0
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			for c := b + 1; c < n; c++ {
+				// shares may come in any order
+				combined, err := CombineShares([]Share{shares[c], shares[a], shares[b]})
+				if err != nil {
+					t.Fatalf("shares %d %d %d: %v", a, b, c, err)
+				}
+				if !Equal(combined, key) {
+					t.Fatalf("shares %d %d %d: combined key differs", a, b, c)
+				}
+				subsets++
+			}
+		}
+	}
+	if subsets != 10 {
+		t.Fatalf("checked %d subsets", subsets)
+	}
+
+	if _, err := CombineShares(shares[:k-1]); !errors.Is(err, errShareCount) {
+		t.Fatalf("expected errShareCount, got %v", err)
+	}
+	if _, err := CombineShares([]Share{shares[0], shares[0], shares[1]}); !errors.Is(err, errShareCount) {
+		t.Fatalf("expected errShareCount for duplicates, got %v", err)
+	}
+}
+
+func TestSplitKeyParams(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		n, k int
+		ok   bool
+	}{
+		{2, 2, true},
+		{255, 2, true},
+		{3, 1, false},
+		{2, 3, false},
+		{256, 2, false},
+	}
+	for _, tt := range tests {
+		_, err := SplitKey(key, tt.n, tt.k)
+		if tt.ok != (err == nil) || (!tt.ok && !errors.Is(err, errShareParams)) {
+			t.Fatalf("%d of %d: got %v", tt.k, tt.n, err)
+		}
+	}
+}
+
+func TestShareEncoding(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := SplitKey(key, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := SplitKey(key, 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed := make([]Share, len(shares))
+	for i, s := range shares {
+		if parsed[i], err = ParseShare(" " + s.String() + "\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	combined, err := CombineShares(parsed[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Equal(combined, key) {
+		t.Fatal("combined key differs")
+	}
+
+	encoded := shares[0].String()
+	raw, err := base64.RawURLEncoding.DecodeString(encoded[len(sharePrefix):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw[3] ^= 1
+	flipped := sharePrefix + base64.RawURLEncoding.EncodeToString(raw)
+	tests := []struct {
+		name    string
+		encoded string
+		err     error
+	}{
+		{"no prefix", encoded[len(sharePrefix):], errShareFormat},
+		{"bad base64", sharePrefix + "!!!!", errShareFormat},
+		{"too short", sharePrefix + "AAAA", errShareFormat},
+		{"checksum", flipped, errShareChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseShare(tt.encoded); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+
+	if _, err := CombineShares([]Share{shares[0], other[1], other[2]}); !errors.Is(err, errShareMismatch) {
+		t.Fatalf("expected errShareMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// parts look like 'rsakys-part:<index>/<total>:<sha256 of the whole>:<base64 chunk>'
+const (
+	partPrefix   = "rsakys-part:"
+	partOverhead = len(partPrefix) + 2*10 + 2 + 43 + 1
+)
+
+// ErrSplitParts is returned when split key material is incomplete, inconsistent, or corrupted
+var ErrSplitParts = errors.New("invalid key parts")
+
+type keyPart struct {
+	index, total int
+	digest       string
+	chunk        []byte
+}
+
+// SplitKeyMaterial splits data, e.g. a PEM encoded key, into parts of at most maxSize bytes,
+// for secret stores capping the size of a single value.
+// Every part records its position and the digest of the whole for JoinKeyMaterial.
+func SplitKeyMaterial(data []byte, maxSize int) ([]string, error) {
+	chunkSize := base64.StdEncoding.DecodedLen(maxSize - partOverhead)
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("%w: part size %d is too small", ErrSplitParts, maxSize)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := base64.RawURLEncoding.EncodeToString(sum[:])
+	total := (len(data) + chunkSize - 1) / chunkSize
+
+	parts := make([]string, 0, total)
+	for i := 0; i < total; i++ {
+		chunk := data[i*chunkSize : min((i+1)*chunkSize, len(data))]
+		parts = append(parts, fmt.Sprintf("%s%d/%d:%s:%s", partPrefix, i+1, total, digest, base64.StdEncoding.EncodeToString(chunk)))
+	}
+
+	return parts, nil
+}
+
+// JoinKeyMaterial reassembles parts created by SplitKeyMaterial in any order
+// and verifies that none is missing, duplicated, or altered.
+// Parts without the 'rsakys-part:' header are concatenated as given, mixing both is an error.
+func JoinKeyMaterial(parts []string) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("%w: no parts", ErrSplitParts)
+	}
+	headers := 0
+	for _, s := range parts {
+		if strings.HasPrefix(strings.TrimSpace(s), partPrefix) {
+			headers++
+		}
+	}
+	switch headers {
+	case 0:
+		return []byte(strings.Join(parts, "")), nil
+	case len(parts):
+	default:
+		return nil, fmt.Errorf("%w: %d of %d parts lack the part header", ErrSplitParts, len(parts)-headers, len(parts))
+	}
+
+	ordered := make([]*keyPart, len(parts))
+	var digest string
+	for _, s := range parts {
+		p, err := parsePart(s)
+		if err != nil {
+			return nil, err
+		}
+		if p.total != len(parts) || (digest != "" && p.digest != digest) {
+			return nil, fmt.Errorf("%w: parts belong to different keys", ErrSplitParts)
+		}
+		if ordered[p.index-1] != nil {
+			return nil, fmt.Errorf("%w: duplicate part %d", ErrSplitParts, p.index)
+		}
+		ordered[p.index-1] = p
+		digest = p.digest
+	}
+
+	return joinParts(ordered, digest)
+}
+
+func parsePart(s string) (*keyPart, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), partPrefix)
+	fields := strings.SplitN(rest, ":", 3)
+	if !ok || len(fields) != 3 {
+		return nil, fmt.Errorf("%w: malformed part", ErrSplitParts)
+	}
+
+	pos, total, _ := strings.Cut(fields[0], "/")
+	p := &keyPart{digest: fields[1]}
+	var err error
+	if p.index, err = strconv.Atoi(pos); err != nil {
+		return nil, fmt.Errorf("%w: malformed part index", ErrSplitParts)
+	}
+	if p.total, err = strconv.Atoi(total); err != nil || p.index < 1 || p.index > p.total {
+		return nil, fmt.Errorf("%w: malformed part index", ErrSplitParts)
+	}
+	if p.chunk, err = base64.StdEncoding.DecodeString(fields[2]); err != nil {
+		return nil, fmt.Errorf("%w: part %d: %v", ErrSplitParts, p.index, err)
+	}
+
+	return p, nil
+}
+
+func joinParts(ordered []*keyPart, digest string) ([]byte, error) {
+	var data []byte
+	for _, p := range ordered {
+		data = append(data, p.chunk...)
+	}
+
+	sum := sha256.Sum256(data)
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(digest)) != 1 {
+		return nil, fmt.Errorf("%w: digest mismatch", ErrSplitParts)
+	}
+
+	return data, nil
+}
+
+// ReadPrivateFromEnv reassembles a private key split across the environment variables
+// '<prefix>_1' to '<prefix>_<n>' and returns the private key struct
+func ReadPrivateFromEnv(prefix string) (*rsa.PrivateKey, error) {
+	var parts []string
+	for i := 1; ; i++ {
+		v, ok := os.LookupEnv(fmt.Sprintf("%s_%d", prefix, i))
+		if !ok {
+			break
+		}
+		parts = append(parts, v)
+	}
+
+	data, err := JoinKeyMaterial(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivate(data)
+}
+
+// ReadPrivateFromFiles reassembles a private key split across files and returns the private key struct,
+// files holding raw chunks must be given in order
+func ReadPrivateFromFiles(paths ...string) (*rsa.PrivateKey, error) {
+	parts := make([]string, 0, len(paths))
+	for _, p := range paths {
+		cntnt, err := readFile(p)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, string(cntnt))
+	}
+
+	data, err := JoinKeyMaterial(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivate(data)
+}
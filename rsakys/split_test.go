@@ -0,0 +1,88 @@
+package rsakys
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestJoinKeyMaterial(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 40)
+	parts, err := SplitKeyMaterial(data, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) < 3 {
+		t.Fatalf("expected at least 3 parts, got %d", len(parts))
+	}
+
+	other, err := SplitKeyMaterial(bytes.ToUpper(data), 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reversed := make([]string, len(parts))
+	for i, p := range parts {
+		reversed[len(parts)-1-i] = p
+	}
+	padded := make([]string, len(parts))
+	for i, p := range parts {
+		padded[i] = "\n " + p + "\r\n"
+	}
+	tampered := append([]string(nil), parts...)
+	tampered[1] = tampered[1][:len(tampered[1])-4] + "AAAA"
+
+	tests := []struct {
+		name  string
+		parts []string
+		want  []byte
+		err   bool
+	}{
+		{name: "in order", parts: parts, want: data},
+		{name: "any order", parts: reversed, want: data},
+		{name: "surrounding whitespace", parts: padded, want: data},
+		{name: "raw chunks", parts: []string{"abc", "def"}, want: []byte("abcdef")},
+		{name: "no parts", parts: nil, err: true},
+		{name: "missing part", parts: parts[1:], err: true},
+		{name: "duplicate part", parts: append([]string{parts[0]}, parts[:len(parts)-1]...), err: true},
+		{name: "tampered part", parts: tampered, err: true},
+		{name: "different keys", parts: append([]string{other[0]}, parts[1:]...), err: true},
+		{name: "mixed headers", parts: append([]string{"raw"}, parts[1:]...), err: true},
+		{name: "mixed headers after whitespace", parts: append([]string{padded[0]}, "raw"), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JoinKeyMaterial(tt.parts)
+			if tt.err {
+				if !errors.Is(err, ErrSplitParts) {
+					t.Fatalf("expected ErrSplitParts, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("joined data differs")
+			}
+		})
+	}
+}
+
+func TestSplitKeyMaterialPartSize(t *testing.T) {
+	data := bytes.Repeat([]byte{0x42}, 1000)
+	parts, err := SplitKeyMaterial(data, 150)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range parts {
+		if len(p) > 150 {
+			t.Fatalf("part %d has %d bytes", i+1, len(p))
+		}
+	}
+
+	if _, err := SplitKeyMaterial(data, partOverhead); !errors.Is(err, ErrSplitParts) {
+		t.Fatalf("expected ErrSplitParts for a too small part size, got %v", err)
+	}
+}
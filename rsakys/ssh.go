@@ -0,0 +1,86 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+
+	"golang.org/x/crypto/ssh"
+)
+
+var errSSHKeyType = errors.New("ssh key is not of type ssh-rsa")
+
+// ToSSHPublicKey converts a public key into an ssh.PublicKey
+func ToSSHPublicKey(publicKey *rsa.PublicKey) (ssh.PublicKey, error) {
+	return ssh.NewPublicKey(publicKey)
+}
+
+// FromSSHPublicKey converts an ssh-rsa ssh.PublicKey into a public key struct
+func FromSSHPublicKey(sshKey ssh.PublicKey) (*rsa.PublicKey, error) {
+	ck, ok := sshKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, errSSHKeyType
+	}
+
+	publicKey, ok := ck.CryptoPublicKey().(*rsa.PublicKey)
+	if !ok {
+		return nil, errSSHKeyType
+	}
+
+	return publicKey, nil
+}
+
+// ToSSHSigner converts a private key into an ssh.Signer, e.g. for ssh.PublicKeys auth or a host key
+func ToSSHSigner(privateKey *rsa.PrivateKey) (ssh.Signer, error) {
+	return ssh.NewSignerFromKey(privateKey)
+}
+
+// ParseSSHPrivateKey parses an OpenSSH or PEM encoded private key, e.g. a file written by ssh-keygen
+func ParseSSHPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errSSHKeyType
+	}
+
+	return privateKey, nil
+}
+
+// MarshalSSHPrivateKey encodes a private key in the OpenSSH private key format
+func MarshalSSHPrivateKey(privateKey *rsa.PrivateKey, comment string) ([]byte, error) {
+	block, err := ssh.MarshalPrivateKey(privateKey, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalAuthorizedKey returns the public key in authorized_keys format
+func MarshalAuthorizedKey(publicKey *rsa.PublicKey) ([]byte, error) {
+	sshKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return ssh.MarshalAuthorizedKey(sshKey), nil
+}
+
+// ParseAuthorizedKey parses a single authorized_keys line and returns the public key struct and its comment
+func ParseAuthorizedKey(line []byte) (*rsa.PublicKey, string, error) {
+	sshKey, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	if err != nil {
+		return nil, "", err
+	}
+
+	publicKey, err := FromSSHPublicKey(sshKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return publicKey, comment, nil
+}
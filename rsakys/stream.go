@@ -0,0 +1,248 @@
+package rsakys
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	streamMagic            = "RKS\x01"
+	streamChunkSize        = 64 * 1024
+	streamNoncePrefixSize  = 7
+	streamMaxRecipients    = 1024
+	streamMaxWrappedKeyLen = 2048
+)
+
+var (
+	errNoRecipients      = errors.New("at least one recipient is required")
+	errTooManyRecipients = errors.New("too many recipients")
+	errStreamSize        = errors.New("stream exceeds the maximum number of chunks")
+)
+
+// EncryptStream encrypts everything read from src for the given RSA public keys and writes it to dst.
+//
+// The stream starts with a header holding a random AES-256 key, encrypted with RSA-OAEP (SHA-256)
+// for every recipient, followed by the plaintext in AES-256-GCM sealed chunks of 64 KiB.
+// Chunk nonces consist of a random prefix, the chunk counter, and a final-chunk flag,
+// so reordered, dropped, or truncated chunks are detected.
+func EncryptStream(dst io.Writer, src io.Reader, recipients ...*rsa.PublicKey) error {
+	return EncryptStreamWithOptions(dst, src, recipients)
+}
+
+// EncryptStreamWithOptions works like EncryptStream, but wraps the AES-256 key with the
+// RSA-OAEP hash and label of the given options
+func EncryptStreamWithOptions(dst io.Writer, src io.Reader, recipients []*rsa.PublicKey, opts ...EncryptOption) error {
+	if len(recipients) == 0 {
+		return errNoRecipients
+	}
+	if len(recipients) > streamMaxRecipients {
+		return fmt.Errorf("%w: %d, at most %d are supported", errTooManyRecipients, len(recipients), streamMaxRecipients)
+	}
+
+	o, err := newEncryptOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(currentConfig().Rand, dataKey); err != nil {
+		return err
+	}
+	prefix := make([]byte, streamNoncePrefixSize)
+	if _, err := io.ReadFull(currentConfig().Rand, prefix); err != nil {
+		return err
+	}
+
+	var header bytes.Buffer
+	header.WriteString(streamMagic)
+	header.Write(prefix)
+	writeUint16(&header, len(recipients))
+	for _, r := range recipients {
+		wrapped, err := rsa.EncryptOAEP(o.hash.New(), currentConfig().Rand, r, dataKey, o.label)
+		if err != nil {
+			return err
+		}
+		writeUint16(&header, len(wrapped))
+		header.Write(wrapped)
+	}
+
+	if _, err := dst.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return err
+	}
+	ad := sha256.Sum256(header.Bytes())
+
+	in := bufio.NewReaderSize(src, streamChunkSize+1)
+	buf := make([]byte, streamChunkSize, streamChunkSize+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(in, buf[:streamChunkSize])
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return err
+		}
+
+		last := n < streamChunkSize
+		if !last {
+			if _, err := in.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+
+		out := aead.Seal(buf[:0], chunkNonce(prefix, counter, last), buf[:n], ad[:])
+		if _, err := dst.Write(out); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		if counter == ^uint32(0) {
+			return errStreamSize
+		}
+	}
+}
+
+// DecryptStream decrypts a stream produced by EncryptStream with the given RSA private key and writes
+// the plaintext to dst, streams of EncryptStreamWithOptions require the same options. Plaintext is written
+// chunk by chunk as soon as each chunk is authenticated, a returned error means the data written so far
+// must not be trusted as complete.
+//
+// Failures are reported as ErrMalformed, ErrWrongKey, ErrTruncated, or a *ChunkError
+// naming the chunk that failed to authenticate.
+func DecryptStream(dst io.Writer, src io.Reader, privateKey *rsa.PrivateKey, opts ...EncryptOption) error {
+	o, err := newEncryptOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	in := bufio.NewReaderSize(src, streamChunkSize+64)
+	aead, prefix, ad, err := openStreamHeader(in, privateKey, o)
+	if err != nil {
+		return err
+	}
+
+	sealedSize := streamChunkSize + aead.Overhead()
+	buf := make([]byte, sealedSize)
+	for counter := uint32(0); ; counter++ {
+		n, err := io.ReadFull(in, buf)
+		if err == io.EOF {
+			return ErrTruncated
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		last := n < sealedSize
+		if !last {
+			if _, err := in.Peek(1); err == io.EOF {
+				last = true
+			}
+		}
+
+		pt, err := aead.Open(nil, chunkNonce(prefix, counter, last), buf[:n], ad)
+		if err != nil {
+			return chunkError(aead, prefix, counter, last, buf[:n], ad)
+		}
+		if _, err := dst.Write(pt); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// VerifyStream checks the integrity of a stream produced by EncryptStream
+// without writing any plaintext, reporting failures like DecryptStream
+func VerifyStream(src io.Reader, privateKey *rsa.PrivateKey, opts ...EncryptOption) error {
+	return DecryptStream(io.Discard, src, privateKey, opts...)
+}
+
+// chunkError tells a stream cut off right after a complete chunk apart from a tampered chunk
+func chunkError(aead cipher.AEAD, prefix []byte, counter uint32, last bool, sealed, ad []byte) error {
+	if last {
+		if _, err := aead.Open(nil, chunkNonce(prefix, counter, false), sealed, ad); err == nil {
+			return ErrTruncated
+		}
+	}
+
+	return &ChunkError{Index: counter}
+}
+
+func openStreamHeader(in io.Reader, privateKey *rsa.PrivateKey, o *encryptOptions) (cipher.AEAD, []byte, []byte, error) {
+	var header bytes.Buffer
+	r := io.TeeReader(in, &header)
+
+	fixed := make([]byte, len(streamMagic)+streamNoncePrefixSize+2)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, nil, nil, ErrMalformed
+	}
+	if string(fixed[:len(streamMagic)]) != streamMagic {
+		return nil, nil, nil, ErrMalformed
+	}
+	prefix := fixed[len(streamMagic) : len(streamMagic)+streamNoncePrefixSize]
+	count := int(binary.BigEndian.Uint16(fixed[len(fixed)-2:]))
+	if count == 0 || count > streamMaxRecipients {
+		return nil, nil, nil, ErrMalformed
+	}
+
+	var dataKey []byte
+	lenBuf := make([]byte, 2)
+	for i := 0; i < count; i++ {
+		if _, err := io.ReadFull(r, lenBuf); err != nil {
+			return nil, nil, nil, ErrMalformed
+		}
+		l := int(binary.BigEndian.Uint16(lenBuf))
+		if l > streamMaxWrappedKeyLen {
+			return nil, nil, nil, ErrMalformed
+		}
+		wrapped := make([]byte, l)
+		if _, err := io.ReadFull(r, wrapped); err != nil {
+			return nil, nil, nil, ErrMalformed
+		}
+
+		if dataKey != nil || len(wrapped) != privateKey.Size() {
+			continue
+		}
+		if k, err := rsa.DecryptOAEP(o.hash.New(), currentConfig().Rand, privateKey, wrapped, o.label); err == nil {
+			dataKey = k
+		}
+	}
+	if dataKey == nil {
+		return nil, nil, nil, ErrWrongKey
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ad := sha256.Sum256(header.Bytes())
+
+	return aead, append([]byte(nil), prefix...), ad[:], nil
+}
+
+func chunkNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[streamNoncePrefixSize:], counter)
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+func writeUint16(buf *bytes.Buffer, v int) {
+	var b [2]byte
+	binary.BigEndian.PutUint16(b[:], uint16(v))
+	buf.Write(b[:])
+}
@@ -0,0 +1,89 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestEncryptStreamRecipients(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tooMany := make([]*rsa.PublicKey, streamMaxRecipients+1)
+	for i := range tooMany {
+		tooMany[i] = &key.PublicKey
+	}
+
+	tests := []struct {
+		name       string
+		recipients []*rsa.PublicKey
+		err        error
+	}{
+		{"none", nil, errNoRecipients},
+		{"too many", tooMany, errTooManyRecipients},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncryptStreamWithOptions(&buf, bytes.NewReader([]byte("x")), tt.recipients); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if buf.Len() != 0 {
+				t.Fatal("rejected stream wrote output")
+			}
+		})
+	}
+}
+
+func TestEncryptStreamRoundTrip(t *testing.T) {
+	alice, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eve, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := []int{0, 1, streamChunkSize - 1, streamChunkSize, 2*streamChunkSize + 17}
+	for _, size := range sizes {
+		plaintext := bytes.Repeat([]byte{0x5a}, size)
+		var ciphertext bytes.Buffer
+		if err := EncryptStream(&ciphertext, bytes.NewReader(plaintext), &alice.PublicKey, &bob.PublicKey); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, key := range []*rsa.PrivateKey{alice, bob} {
+			var out bytes.Buffer
+			if err := DecryptStream(&out, bytes.NewReader(ciphertext.Bytes()), key); err != nil {
+				t.Fatalf("size %d: %v", size, err)
+			}
+			if !bytes.Equal(out.Bytes(), plaintext) {
+				t.Fatalf("size %d: plaintext differs", size)
+			}
+		}
+
+		var out bytes.Buffer
+		if err := DecryptStream(&out, bytes.NewReader(ciphertext.Bytes()), eve); err == nil {
+			t.Fatalf("size %d: decrypted for a key that is no recipient", size)
+		}
+
+		truncated := ciphertext.Bytes()[:ciphertext.Len()-1]
+		if err := DecryptStream(&out, bytes.NewReader(truncated), alice); err == nil {
+			t.Fatalf("size %d: accepted a truncated stream", size)
+		}
+
+		flipped := bytes.Clone(ciphertext.Bytes())
+		flipped[len(flipped)-1] ^= 1
+		if err := DecryptStream(&out, bytes.NewReader(flipped), alice); err == nil {
+			t.Fatalf("size %d: accepted a modified stream", size)
+		}
+	}
+}
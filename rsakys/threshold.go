@@ -0,0 +1,420 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"filippo.io/bigmod"
+)
+
+// This file holds an experimental t-of-n threshold variant of RSA PKCS1 v1.5 signing after
+// Shoup, "Practical Threshold Signatures" (2000). A dealer splits the private exponent once, the parties
+// sign with their shares, and a coordinator combines any t signature shares into a regular signature
+// without the key ever being reconstructed. Shares are not verifiable on their own, a bad share is only
+// detected when a combined signature fails to verify, the coordinator then tries other sets of shares.
+//
+// As in Shoup's scheme the key must consist of safe primes p = 2p'+1 and q = 2q'+1, see GenerateThresholdKey.
+// The exponent is shared modulo p'q', which has no small factors, so fewer than threshold shares reveal
+// nothing about d. Parties apply their shares with constant time arithmetic.
+
+// Errors of threshold signing
+var (
+	ErrThresholdParams = errors.New("invalid threshold parameters")
+	ErrShareMismatch   = errors.New("signature share does not belong to this signing session")
+	ErrBadShares       = errors.New("combined signature is invalid, at least one share is bad")
+)
+
+// digestInfo prefixes of EMSA-PKCS1-v1_5, RFC 8017 section 9.2
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+// ThresholdShare is the secret share of a single party
+type ThresholdShare struct {
+	Index     int
+	Threshold int
+	Parties   int
+	PublicKey *rsa.PublicKey
+	Secret    *big.Int
+}
+
+// SignatureShare is the contribution of a single party to a threshold signature
+type SignatureShare struct {
+	Index int
+	Value *big.Int
+}
+
+// GenerateThresholdKey generates an RSA key of safe primes as required by SplitThreshold.
+// Safe primes are rare, expect generation to take considerably longer than for a regular key.
+func GenerateThresholdKey(bitSize int) (*rsa.PrivateKey, error) {
+	if bitSize == 0 {
+		bitSize = DefaultBits
+	}
+	if err := ValidateBits(bitSize); err != nil {
+		return nil, err
+	}
+
+	return generateSafePrimeKey(bitSize)
+}
+
+func generateSafePrimeKey(bitSize int) (*rsa.PrivateKey, error) {
+	for {
+		p, err := safePrime(bitSize / 2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := safePrime(bitSize - bitSize/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		// both primes have their top two bits set, so n has exactly bitSize bits
+		n := new(big.Int).Mul(p, q)
+		one := big.NewInt(1)
+		p1 := new(big.Int).Sub(p, one)
+		q1 := new(big.Int).Sub(q, one)
+		lambda := new(big.Int).Div(new(big.Int).Mul(p1, q1), new(big.Int).GCD(nil, nil, p1, q1))
+		e := big.NewInt(65537)
+		d := new(big.Int).ModInverse(e, lambda)
+		if d == nil {
+			continue
+		}
+
+		key := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: n, E: int(e.Int64())},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		key.Precompute()
+		if err := key.Validate(); err != nil {
+			return nil, err
+		}
+
+		return key, nil
+	}
+}
+
+// safePrime returns a prime p = 2p'+1 of the given bit length with p' prime
+func safePrime(bits int) (*big.Int, error) {
+	for {
+		half, err := rand.Prime(currentConfig().Rand, bits-1)
+		if err != nil {
+			return nil, err
+		}
+		p := new(big.Int).Lsh(half, 1)
+		p.SetBit(p, 0, 1)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// isSafePrime reports whether p and (p-1)/2 are both prime
+func isSafePrime(p *big.Int) bool {
+	half := new(big.Int).Rsh(p, 1)
+
+	return p.Bit(0) == 1 && half.ProbablyPrime(20) && p.ProbablyPrime(20)
+}
+
+// SplitThreshold splits a private key into shares for parties, any threshold of which can sign together.
+// The key must consist of safe primes, e.g. from GenerateThresholdKey, other keys are rejected.
+// The private key should be destroyed afterwards, it is not needed for signing anymore.
+// Experimental, see the notes at the top of this file.
+func SplitThreshold(privateKey *rsa.PrivateKey, threshold, parties int) ([]*ThresholdShare, error) {
+	if threshold < 1 || threshold > parties || parties >= privateKey.E || len(privateKey.Primes) != 2 {
+		return nil, fmt.Errorf("%w: %d of %d", ErrThresholdParams, threshold, parties)
+	}
+	if err := checkSigningKey(privateKey.N); err != nil {
+		return nil, err
+	}
+	p, q := privateKey.Primes[0], privateKey.Primes[1]
+	if !isSafePrime(p) || !isSafePrime(q) {
+		return nil, fmt.Errorf("%w: the key does not consist of safe primes", ErrThresholdParams)
+	}
+
+	// the exponent is shared modulo m = p'q', the order of the squares in Z_n*
+	m := new(big.Int).Mul(new(big.Int).Rsh(p, 1), new(big.Int).Rsh(q, 1))
+
+	// f(x) = d + a_1 x + ... + a_{t-1} x^{t-1} mod m
+	coeffs := []*big.Int{new(big.Int).Mod(privateKey.D, m)}
+	for i := 1; i < threshold; i++ {
+		a, err := rand.Int(currentConfig().Rand, m)
+		if err != nil {
+			return nil, err
+		}
+		coeffs = append(coeffs, a)
+	}
+
+	shares := make([]*ThresholdShare, parties)
+	for i := range shares {
+		shares[i] = &ThresholdShare{
+			Index:     i + 1,
+			Threshold: threshold,
+			Parties:   parties,
+			PublicKey: &privateKey.PublicKey,
+			Secret:    evalPoly(coeffs, int64(i+1), m),
+		}
+	}
+
+	return shares, nil
+}
+
+// Sign computes the signature share of a digest, x^(2Δs_i) mod n, in constant time
+func (s *ThresholdShare) Sign(hash crypto.Hash, digest []byte) (*SignatureShare, error) {
+	n := s.PublicKey.N
+	if s.Secret == nil || s.Secret.Sign() < 0 || s.Secret.Cmp(n) >= 0 {
+		return nil, fmt.Errorf("%w: share out of range", ErrThresholdParams)
+	}
+	// the shares keep the modulus of the split key, and with it its expiry
+	if err := checkSigningKey(n); err != nil {
+		return nil, err
+	}
+	x, err := emsaPKCS1v15(s.PublicKey, hash, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	N, err := bigmod.NewModulusFromBig(n)
+	if err != nil {
+		return nil, err
+	}
+	xn, err := bigmod.NewNat().SetBytes(x.Bytes(), N)
+	if err != nil {
+		return nil, err
+	}
+
+	// the exponent is padded to the length of its bound, so its size does not leak through timing
+	twoDelta := new(big.Int).Mul(big.NewInt(2), factorial(s.Parties))
+	exp := new(big.Int).Mul(twoDelta, s.Secret)
+	bound := new(big.Int).Mul(twoDelta, n)
+	y := bigmod.NewNat().Exp(xn, exp.FillBytes(make([]byte, len(bound.Bytes()))), N)
+
+	return &SignatureShare{Index: s.Index, Value: new(big.Int).SetBytes(y.Bytes(N))}, nil
+}
+
+// ThresholdCoordinator collects the signature shares of one digest and combines them
+// once enough parties contributed
+type ThresholdCoordinator struct {
+	publicKey *rsa.PublicKey
+	threshold int
+	parties   int
+	hash      crypto.Hash
+	digest    []byte
+
+	mu     sync.Mutex
+	shares map[int]*big.Int
+}
+
+// NewThresholdCoordinator starts a signing session for digest
+func NewThresholdCoordinator(publicKey *rsa.PublicKey, threshold, parties int, hash crypto.Hash, digest []byte) *ThresholdCoordinator {
+	return &ThresholdCoordinator{
+		publicKey: publicKey,
+		threshold: threshold,
+		parties:   parties,
+		hash:      hash,
+		digest:    digest,
+		shares:    make(map[int]*big.Int),
+	}
+}
+
+// Add records a signature share, adding the same party twice replaces its share
+func (c *ThresholdCoordinator) Add(share *SignatureShare) error {
+	if share.Index < 1 || share.Index > c.parties || share.Value == nil ||
+		share.Value.Sign() <= 0 || share.Value.Cmp(c.publicKey.N) >= 0 {
+		return ErrShareMismatch
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.shares[share.Index] = share.Value
+
+	return nil
+}
+
+// Ready reports whether enough shares were added to combine them
+func (c *ThresholdCoordinator) Ready() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.shares) >= c.threshold
+}
+
+// Signature combines threshold shares into a PKCS1 v1.5 signature verifiable with rsa.VerifyPKCS1v15.
+// If more shares than needed were added, the sets of threshold shares are tried in turn until one
+// verifies, so bad shares only fail the signature if no set of good ones is left.
+func (c *ThresholdCoordinator) Signature() ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.shares) < c.threshold {
+		return nil, fmt.Errorf("%w: %d of %d shares", ErrThresholdParams, len(c.shares), c.threshold)
+	}
+	x, err := emsaPKCS1v15(c.publicKey, c.hash, c.digest)
+	if err != nil {
+		return nil, err
+	}
+
+	indices := make([]int, 0, len(c.shares))
+	for i := range c.shares {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	var sig []byte
+	err = forEachSubset(indices, c.threshold, func(set []int) (bool, error) {
+		y, err := c.combine(x, set)
+		if err != nil {
+			return false, err
+		}
+		candidate := y.FillBytes(make([]byte, c.publicKey.Size()))
+		if rsa.VerifyPKCS1v15(c.publicKey, c.hash, c.digest, candidate) != nil {
+			return false, nil
+		}
+		sig = candidate
+
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, ErrBadShares
+	}
+
+	return sig, nil
+}
+
+// forEachSubset calls fn with every subset of size k of indices in lexicographic order until fn is done
+func forEachSubset(indices []int, k int, fn func(set []int) (bool, error)) error {
+	pos := make([]int, k)
+	for i := range pos {
+		pos[i] = i
+	}
+	set := make([]int, k)
+	for {
+		for i, p := range pos {
+			set[i] = indices[p]
+		}
+		done, err := fn(set)
+		if done || err != nil {
+			return err
+		}
+
+		// advance the rightmost position that can still move
+		i := k - 1
+		for i >= 0 && pos[i] == len(indices)-k+i {
+			i--
+		}
+		if i < 0 {
+			return nil
+		}
+		pos[i]++
+		for j := i + 1; j < k; j++ {
+			pos[j] = pos[j-1] + 1
+		}
+	}
+}
+
+// combine computes w = Π x_i^(2λ_i) = x^(4Δ²d) over set and turns it into x^d using e'a + eb = 1 for e' = 4Δ²
+func (c *ThresholdCoordinator) combine(x *big.Int, set []int) (*big.Int, error) {
+	n := c.publicKey.N
+	delta := factorial(c.parties)
+	w := big.NewInt(1)
+	for _, i := range set {
+		exp := new(big.Int).Mul(big.NewInt(2), lagrangeAtZero(set, i, delta))
+		w.Mul(w, expMod(c.shares[i], exp, n))
+		w.Mod(w, n)
+	}
+
+	ePrime := new(big.Int).Mul(big.NewInt(4), new(big.Int).Mul(delta, delta))
+	a, b := new(big.Int), new(big.Int)
+	if new(big.Int).GCD(a, b, ePrime, big.NewInt(int64(c.publicKey.E))).Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrThresholdParams
+	}
+
+	y := expMod(w, a, n)
+	y.Mul(y, expMod(x, b, n))
+
+	return y.Mod(y, n), nil
+}
+
+// lagrangeAtZero returns the integer coefficient Δ Π_{j≠i} j/(j-i) of party i
+func lagrangeAtZero(set []int, i int, delta *big.Int) *big.Int {
+	num := new(big.Int).Set(delta)
+	den := big.NewInt(1)
+	for _, j := range set {
+		if j == i {
+			continue
+		}
+		num.Mul(num, big.NewInt(int64(j)))
+		den.Mul(den, big.NewInt(int64(j-i)))
+	}
+
+	return num.Quo(num, den)
+}
+
+// expMod supports negative exponents by inverting the base
+func expMod(x, exp, n *big.Int) *big.Int {
+	if exp.Sign() >= 0 {
+		return new(big.Int).Exp(x, exp, n)
+	}
+
+	inv := new(big.Int).ModInverse(x, n)
+	if inv == nil {
+		// x shares a factor with n, which only happens for maliciously chosen shares
+		return big.NewInt(0)
+	}
+
+	return new(big.Int).Exp(inv, new(big.Int).Neg(exp), n)
+}
+
+func evalPoly(coeffs []*big.Int, x int64, m *big.Int) *big.Int {
+	result := new(big.Int)
+	bx := big.NewInt(x)
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result.Mul(result, bx)
+		result.Add(result, coeffs[i])
+		result.Mod(result, m)
+	}
+
+	return result
+}
+
+func factorial(n int) *big.Int {
+	return new(big.Int).MulRange(1, int64(n))
+}
+
+// emsaPKCS1v15 returns the padded digest 0x00 0x01 0xff.. 0x00 DigestInfo as integer
+func emsaPKCS1v15(publicKey *rsa.PublicKey, hash crypto.Hash, digest []byte) (*big.Int, error) {
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok || len(digest) != hash.Size() {
+		return nil, fmt.Errorf("%w: unsupported hash or digest length", ErrThresholdParams)
+	}
+
+	k := publicKey.Size()
+	tLen := len(prefix) + len(digest)
+	if k < tLen+11 {
+		return nil, rsa.ErrMessageTooLong
+	}
+
+	em := make([]byte, k)
+	em[1] = 0x01
+	for i := 2; i < k-tLen-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-tLen:], prefix)
+	copy(em[k-len(digest):], digest)
+
+	return new(big.Int).SetBytes(em), nil
+}
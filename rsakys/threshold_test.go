@@ -0,0 +1,116 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func thresholdShares(t *testing.T, threshold, parties int) (*rsa.PublicKey, []*ThresholdShare) {
+	t.Helper()
+	// small keys keep safe prime generation fast, newer Go versions refuse them by default
+	t.Setenv("GODEBUG", "rsa1024min=0")
+
+	key, err := generateSafePrimeKey(512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := SplitThreshold(key, threshold, parties)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &key.PublicKey, shares
+}
+
+func TestThresholdSignatureVerifies(t *testing.T) {
+	publicKey, shares := thresholdShares(t, 3, 5)
+	digest := sha256.Sum256([]byte("release v1.0.0"))
+
+	tests := []struct {
+		name    string
+		parties []int
+	}{
+		{"first", []int{1, 2, 3}},
+		{"last", []int{3, 4, 5}},
+		{"spread", []int{1, 3, 5}},
+		{"more than needed", []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewThresholdCoordinator(publicKey, 3, 5, crypto.SHA256, digest[:])
+			for _, i := range tt.parties {
+				share, err := shares[i-1].Sign(crypto.SHA256, digest[:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Add(share); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sig, err := c.Signature()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestThresholdSignatureSkipsBadShares(t *testing.T) {
+	publicKey, shares := thresholdShares(t, 3, 5)
+	digest := sha256.Sum256([]byte("release v1.0.0"))
+
+	tests := []struct {
+		name    string
+		bad     map[int]bool
+		wantErr error
+	}{
+		{"one bad of four", map[int]bool{1: true}, nil},
+		{"two bad of four", map[int]bool{1: true, 4: true}, ErrBadShares},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewThresholdCoordinator(publicKey, 3, 5, crypto.SHA256, digest[:])
+			for _, i := range []int{1, 2, 3, 4} {
+				share, err := shares[i-1].Sign(crypto.SHA256, digest[:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if tt.bad[i] {
+					share.Value = new(big.Int).Add(share.Value, big.NewInt(1))
+				}
+				if err := c.Add(share); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sig, err := c.Signature()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], sig); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitThresholdRejectsRegularPrimes(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SplitThreshold(key, 2, 3); !errors.Is(err, ErrThresholdParams) {
+		t.Fatalf("got %v, want %v", err, ErrThresholdParams)
+	}
+}
@@ -0,0 +1,358 @@
+package rsakys
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	certType        = "CERTIFICATE"
+	certSuffix      = "crt"
+	certValidity    = 365 * 24 * time.Hour
+	certRenewBefore = 30 * 24 * time.Hour
+	tlsKeyname      = "tls"
+)
+
+// Errors of misconfigured certificate and key pairs
+var (
+	ErrCertKeyMismatch = errors.New("certificate does not match the private key")
+	ErrCertExpired     = errors.New("certificate is expired or not yet valid")
+	errNoCertificates  = errors.New("no PEM certificates found")
+	errCertFileSize    = errors.New("certificate file is too large")
+)
+
+// maxCertFileSize bounds certificate files, system CA bundles stay well below it
+const maxCertFileSize int64 = 4 << 20
+
+// CreateSelfSignedCert returns a PEM encoded self-signed certificate for the given host names and IPs,
+// valid from now for the given duration
+func CreateSelfSignedCert(privateKey *rsa.PrivateKey, hosts []string, validity time.Duration) ([]byte, error) {
+	serial, err := rand.Int(currentConfig().Rand, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "localhost"},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+	}
+	if len(hosts) > 0 {
+		tmpl.Subject.CommonName = hosts[0]
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(currentConfig().Rand, tmpl, tmpl, &privateKey.PublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: certType, Bytes: der}), nil
+}
+
+// LoadOrCreateTLSCertificate loads the keypair 'tls.pem' and certificate 'tls.crt' from path
+// and creates them on first run. The certificate is replaced when it expires within 30 days,
+// does not match the key, or misses one of the hosts, which default to the hostname and loopback.
+func LoadOrCreateTLSCertificate(path string, hosts ...string) (tls.Certificate, error) {
+	if len(hosts) == 0 {
+		hosts = defaultHosts()
+	}
+
+	privateKey, err := GenerateKeypairIfNotExists(path, tlsKeyname, 0)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	certPath := keyFilePath(path, tlsKeyname, certSuffix)
+	certPEM, err := readFile(certPath)
+	if err != nil || !certUsable(certPEM, privateKey, hosts) {
+		if certPEM, err = CreateSelfSignedCert(privateKey, hosts, certValidity); err != nil {
+			return tls.Certificate{}, err
+		}
+		err = writeAtomic(certPath, currentConfig().PublicPerm, func(w io.Writer) error {
+			_, err := io.Copy(w, bytes.NewReader(certPEM))
+			return err
+		})
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	block, _ := pem.Decode(certPEM)
+
+	return tls.Certificate{
+		Certificate: [][]byte{block.Bytes},
+		PrivateKey:  privateKey,
+	}, nil
+}
+
+// ListenAndServeTLS starts an HTTPS server on addr using the certificate of LoadOrCreateTLSCertificate,
+// e.g. for internal tools and admin UIs that need TLS without a certificate authority
+func ListenAndServeTLS(addr, path string, handler http.Handler, hosts ...string) error {
+	cert, err := LoadOrCreateTLSCertificate(path, hosts...)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{cert},
+		},
+	}
+
+	return srv.ListenAndServeTLS("", "")
+}
+
+func certUsable(certPEM []byte, privateKey *rsa.PrivateKey, hosts []string) bool {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != certType {
+		return false
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil || time.Until(cert.NotAfter) < certRenewBefore || !privateKey.PublicKey.Equal(cert.PublicKey) {
+		return false
+	}
+
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func defaultHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if name, err := os.Hostname(); err == nil && name != "" {
+		hosts = append(hosts, name)
+	}
+
+	return hosts
+}
+
+// loadKeyPair reads a PEM certificate chain and its RSA private key
+// and checks that they belong together and the leaf is currently valid
+func loadKeyPair(certPath, keyPath string) (tls.Certificate, error) {
+	chain, err := readCertificates(certPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	cntnt, err := readFile(keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	privateKey, err := Parse[*rsa.PrivateKey](cntnt)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("%s: %w", keyPath, err)
+	}
+
+	leaf := chain[0]
+	if !privateKey.PublicKey.Equal(leaf.PublicKey) {
+		return tls.Certificate{}, fmt.Errorf("%w: %s and %s", ErrCertKeyMismatch, certPath, keyPath)
+	}
+	if now := time.Now(); now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("%w: %s valid from %s to %s", ErrCertExpired, certPath, leaf.NotBefore, leaf.NotAfter)
+	}
+
+	cert := tls.Certificate{PrivateKey: privateKey, Leaf: leaf}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+
+	return cert, nil
+}
+
+// loadCertPool returns a pool of the certificates in caPath, or the system pool if caPath is empty
+func loadCertPool(caPath string) (*x509.CertPool, error) {
+	if caPath == "" {
+		return x509.SystemCertPool()
+	}
+
+	certs, err := readCertificates(caPath)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	for _, c := range certs {
+		pool.AddCert(c)
+	}
+
+	return pool, nil
+}
+
+// readCertificates reads all certificates of a PEM file, e.g. a chain or a CA bundle,
+// files above maxCertFileSize are rejected instead of being cut short
+func readCertificates(path string) ([]*x509.Certificate, error) {
+	f, err := os.Open(fixPath(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cntnt, err := io.ReadAll(io.LimitReader(f, maxCertFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(cntnt)) > maxCertFileSize {
+		return nil, fmt.Errorf("%w: %s exceeds %d bytes", errCertFileSize, path, maxCertFileSize)
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(cntnt); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != certType {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoCertificates, path)
+	}
+
+	return certs, nil
+}
+
+// ServerTLSConfig returns a TLS server configuration for the certificate and RSA key in certPath and keyPath.
+// Replaced files are picked up on the next handshake, so rotated certificates need no restart.
+// If caPath is set, clients must present a certificate issued by one of its CAs (mTLS).
+func ServerTLSConfig(certPath, keyPath, caPath string) (*tls.Config, error) {
+	r, err := newCertReloader(certPath, keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.get()
+		},
+	}
+	if caPath != "" {
+		if cfg.ClientCAs, err = loadCertPool(caPath); err != nil {
+			return nil, err
+		}
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return cfg, nil
+}
+
+// ClientTLSConfig returns a TLS client configuration trusting the CAs in caPath, or the system roots if it is empty.
+// If certPath and keyPath are set, the client authenticates with them (mTLS) and picks up replaced files
+// on the next handshake.
+func ClientTLSConfig(certPath, keyPath, caPath string) (*tls.Config, error) {
+	pool, err := loadCertPool(caPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+	}
+	if certPath != "" || keyPath != "" {
+		r, err := newCertReloader(certPath, keyPath)
+		if err != nil {
+			return nil, err
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.get()
+		}
+	}
+
+	return cfg, nil
+}
+
+// certReloader reloads a certificate and key pair once one of the files changed,
+// a broken replacement keeps the previous pair in use
+type certReloader struct {
+	certPath, keyPath string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+func newCertReloader(certPath, keyPath string) (*certReloader, error) {
+	r := &certReloader{certPath: certPath, keyPath: keyPath}
+	if _, err := r.get(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) get() (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTime, err := r.latestModTime()
+	if err != nil && r.cert != nil {
+		return r.cert, nil
+	}
+	if err != nil || !modTime.After(r.modTime) {
+		return r.cert, err
+	}
+
+	cert, err := loadKeyPair(r.certPath, r.keyPath)
+	if err != nil {
+		if r.cert != nil {
+			r.modTime = modTime
+			currentConfig().Logger.Printf("rsakys: keeping previous certificate, reload of %s failed: %v", r.certPath, err)
+			return r.cert, nil
+		}
+		return nil, err
+	}
+	r.cert = &cert
+	r.modTime = modTime
+
+	return r.cert, nil
+}
+
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, p := range []string{r.certPath, r.keyPath} {
+		info, err := os.Stat(fixPath(p))
+		if err != nil {
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,51 @@
+package rsakys
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReadCertificatesBundle(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a bundle well above the 10 KiB limit of key files
+	var bundle bytes.Buffer
+	const n = 20
+	for i := 0; i < n; i++ {
+		cert, err := CreateSelfSignedCert(key, []string{"localhost"}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bundle.Write(cert)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.crt")
+	if err := os.WriteFile(path, bundle.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	certs, err := readCertificates(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != n {
+		t.Fatalf("read %d of %d certificates", len(certs), n)
+	}
+}
+
+func TestReadCertificatesTooLarge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "large.crt")
+	if err := os.WriteFile(path, make([]byte, maxCertFileSize+1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := readCertificates(path); !errors.Is(err, errCertFileSize) {
+		t.Fatalf("got %v, want %v", err, errCertFileSize)
+	}
+}
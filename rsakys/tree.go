@@ -0,0 +1,132 @@
+package rsakys
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const encryptedSuffix = "enc"
+
+var errOverlappingDirs = errors.New("destination directory overlaps the source directory")
+
+// EncryptDir encrypts every regular file below srcDir for the given RSA public keys and writes it
+// to the same relative path below dstDir with an '.enc' suffix, preserving file and directory modes.
+// dstDir must not be srcDir or lie below it.
+func EncryptDir(srcDir, dstDir string, recipients ...*rsa.PublicKey) error {
+	return EncryptDirWithOptions(srcDir, dstDir, recipients)
+}
+
+// EncryptDirWithOptions works like EncryptDir, but encrypts with the RSA-OAEP hash and label of the given options
+func EncryptDirWithOptions(srcDir, dstDir string, recipients []*rsa.PublicKey, opts ...EncryptOption) error {
+	if len(recipients) == 0 {
+		return errNoRecipients
+	}
+
+	return walkTree(srcDir, dstDir, func(rel string) (string, bool) {
+		return rel + "." + encryptedSuffix, true
+	}, func(dst io.Writer, src io.Reader) error {
+		return EncryptStreamWithOptions(dst, src, recipients, opts...)
+	})
+}
+
+// DecryptDir decrypts every '.enc' file below srcDir with the given RSA private key and writes it
+// to the same relative path below dstDir without the suffix, preserving file and directory modes.
+// dstDir must not be srcDir or lie below it.
+func DecryptDir(srcDir, dstDir string, privateKey *rsa.PrivateKey, opts ...EncryptOption) error {
+	return walkTree(srcDir, dstDir, func(rel string) (string, bool) {
+		if !hasSuffix(rel, encryptedSuffix) {
+			return "", false
+		}
+		return trimSuffix(rel, encryptedSuffix), true
+	}, func(dst io.Writer, src io.Reader) error {
+		return DecryptStream(dst, src, privateKey, opts...)
+	})
+}
+
+func walkTree(
+	srcDir, dstDir string,
+	target func(rel string) (string, bool),
+	fn func(dst io.Writer, src io.Reader) error,
+) error {
+	if err := checkOverlap(srcDir, dstDir); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(filepath.Join(dstDir, rel), info.Mode().Perm())
+		case d.Type().IsRegular():
+			out, ok := target(rel)
+			if !ok {
+				return nil
+			}
+			return transformFile(p, filepath.Join(dstDir, out), fn)
+		default:
+			return nil
+		}
+	})
+}
+
+// checkOverlap rejects a dstDir equal to or below srcDir, the walk would pick up its own output
+func checkOverlap(srcDir, dstDir string) error {
+	src, err := resolvePath(srcDir)
+	if err != nil {
+		return err
+	}
+	dst, err := resolvePath(dstDir)
+	if err != nil {
+		return err
+	}
+	if caseInsensitivePaths {
+		src, dst = strings.ToLower(src), strings.ToLower(dst)
+	}
+
+	rel, err := filepath.Rel(src, dst)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%w: %s is inside %s", errOverlappingDirs, dstDir, srcDir)
+	}
+
+	return nil
+}
+
+// resolvePath returns the absolute path of p with symlinks resolved, a missing tail is kept as is
+func resolvePath(p string) (string, error) {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	resolved, err := filepath.EvalSymlinks(abs)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return resolved, err
+	}
+	parent := filepath.Dir(abs)
+	if parent == abs {
+		return abs, nil
+	}
+	if parent, err = resolvePath(parent); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(parent, filepath.Base(abs)), nil
+}
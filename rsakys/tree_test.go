@@ -0,0 +1,85 @@
+package rsakys
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDirRoundTrip(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	mustWrite(t, filepath.Join(src, "a.txt"), "a", 0o644)
+	mustWrite(t, filepath.Join(src, "sub", "b.txt"), "b", 0o600)
+
+	enc, dec := filepath.Join(root, "enc"), filepath.Join(root, "dec")
+	if err := EncryptDir(src, enc, &key.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := DecryptDir(enc, dec, key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rel := range []string{"a.txt", filepath.Join("sub", "b.txt")} {
+		want, err := os.ReadFile(filepath.Join(src, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := os.ReadFile(filepath.Join(dec, rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(want) {
+			t.Fatalf("%s: got %q, want %q", rel, got, want)
+		}
+	}
+}
+
+func TestEncryptDirRejectsOverlap(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	src := filepath.Join(root, "src")
+	mustWrite(t, filepath.Join(src, "a.txt"), "a", 0o644)
+	link := filepath.Join(root, "link")
+	if err := os.Symlink(src, link); err != nil {
+		t.Logf("symlinks unsupported: %v", err)
+		link = src
+	}
+
+	tests := []struct {
+		name     string
+		src, dst string
+		err      bool
+	}{
+		{"same", src, src, true},
+		{"inside", src, filepath.Join(src, "out"), true},
+		{"nested missing", src, filepath.Join(src, "x", "y"), true},
+		{"relative", src, filepath.Join(src, "..", "src", "out"), true},
+		{"through symlink", src, filepath.Join(link, "out"), true},
+		{"sibling", src, filepath.Join(root, "src-out"), false},
+		{"parent", src, root, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EncryptDir(tt.src, tt.dst, &key.PublicKey)
+			if tt.err != errors.Is(err, errOverlappingDirs) {
+				t.Fatalf("got %v", err)
+			}
+			if tt.err {
+				if err := DecryptDir(tt.src, tt.dst, key); !errors.Is(err, errOverlappingDirs) {
+					t.Fatalf("decrypt: got %v", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeyUsage is a set of purposes a key may be used for
+type KeyUsage uint
+
+// Key purposes, combine them with '|'
+const (
+	// UsageSign allows any signature. A signer only sees a digest and cannot tell certificates
+	// from other data, so this includes issuing certificates, e.g. through x509.CreateCertificate.
+	UsageSign KeyUsage = 1 << iota
+	// UsageDecrypt allows decryption
+	UsageDecrypt
+	// UsageCertSign allows issuing certificates through PolicyKey.CreateCertificate only,
+	// use it without UsageSign for keys that must not sign anything else
+	UsageCertSign
+)
+
+var usageNames = []struct {
+	usage KeyUsage
+	name  string
+}{
+	{UsageSign, "sign"},
+	{UsageDecrypt, "decrypt"},
+	{UsageCertSign, "cert-sign"},
+}
+
+// ErrKeyUsage is returned for operations outside of a key's declared usage
+var ErrKeyUsage = errors.New("operation not allowed by key usage")
+
+var errUnknownUsage = errors.New("unknown key usage")
+
+// String returns the comma separated purposes, e.g. 'sign,decrypt'
+func (u KeyUsage) String() string {
+	var names []string
+	for _, n := range usageNames {
+		if u&n.usage != 0 {
+			names = append(names, n.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+// ParseKeyUsage parses comma separated purposes as written by KeyUsage.String,
+// e.g. the Usage field of a key's Metadata
+func ParseKeyUsage(s string) (KeyUsage, error) {
+	var u KeyUsage
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		found := false
+		for _, n := range usageNames {
+			if n.name == field {
+				u |= n.usage
+				found = true
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("%w: %q", errUnknownUsage, field)
+		}
+	}
+
+	return u, nil
+}
+
+// PolicyKey restricts a private key to its declared usage, so a handle passed around a codebase
+// cannot be used for other purposes. It implements crypto.Signer and crypto.Decrypter.
+// A sign-only key can still issue certificates, see UsageSign.
+type PolicyKey struct {
+	key   *rsa.PrivateKey
+	usage KeyUsage
+}
+
+var (
+	_ crypto.Signer    = (*PolicyKey)(nil)
+	_ crypto.Decrypter = (*PolicyKey)(nil)
+)
+
+// NewPolicyKey wraps a private key, allowing only the given usage
+func NewPolicyKey(privateKey *rsa.PrivateKey, usage KeyUsage) *PolicyKey {
+	return &PolicyKey{key: privateKey, usage: usage}
+}
+
+// Usage returns the declared usage of the key
+func (k *PolicyKey) Usage() KeyUsage {
+	return k.usage
+}
+
+// Allows reports whether all purposes of u are part of the declared usage
+func (k *PolicyKey) Allows(u KeyUsage) bool {
+	return k.usage&u == u
+}
+
+// Public returns the public key, it is available regardless of the usage
+func (k *PolicyKey) Public() crypto.PublicKey {
+	return &k.key.PublicKey
+}
+
+// Sign signs digest, it requires UsageSign
+func (k *PolicyKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if err := k.check(UsageSign); err != nil {
+		return nil, err
+	}
+	if err := checkSigningKey(k.key.N); err != nil {
+		return nil, err
+	}
+
+	return k.key.Sign(rand, digest, opts)
+}
+
+// Decrypt decrypts msg, it requires UsageDecrypt
+func (k *PolicyKey) Decrypt(rand io.Reader, msg []byte, opts crypto.DecrypterOpts) ([]byte, error) {
+	if err := k.check(UsageDecrypt); err != nil {
+		return nil, err
+	}
+
+	return k.key.Decrypt(rand, msg, opts)
+}
+
+// CreateCertificate issues a DER encoded certificate signed by the key,
+// it requires UsageCertSign or UsageSign
+func (k *PolicyKey) CreateCertificate(template, parent *x509.Certificate, publicKey any) ([]byte, error) {
+	if !k.Allows(UsageSign) {
+		if err := k.check(UsageCertSign); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkSigningKey(k.key.N); err != nil {
+		return nil, err
+	}
+
+	return x509.CreateCertificate(currentConfig().Rand, template, parent, publicKey, k.key)
+}
+
+func (k *PolicyKey) check(u KeyUsage) error {
+	if !k.Allows(u) {
+		return fmt.Errorf("%w: requires %s, key allows %q", ErrKeyUsage, u, k.usage)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package rsakys
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestKeyUsageString(t *testing.T) {
+	tests := []struct {
+		usage KeyUsage
+		s     string
+	}{
+		{0, ""},
+		{UsageSign, "sign"},
+		{UsageSign | UsageDecrypt, "sign,decrypt"},
+		{UsageSign | UsageDecrypt | UsageCertSign, "sign,decrypt,cert-sign"},
+	}
+	for _, tt := range tests {
+		if got := tt.usage.String(); got != tt.s {
+			t.Fatalf("%d: got %q, want %q", tt.usage, got, tt.s)
+		}
+		if got, err := ParseKeyUsage(tt.s); err != nil || got != tt.usage {
+			t.Fatalf("%q: got %d, %v", tt.s, got, err)
+		}
+	}
+
+	if _, err := ParseKeyUsage("sign,encrypt"); !errors.Is(err, errUnknownUsage) {
+		t.Fatalf("expected errUnknownUsage, got %v", err)
+	}
+}
+
+func TestPolicyKey(t *testing.T) {
+	key, err := GetPrivateKey(Bits2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("msg"))
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, &key.PublicKey, []byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	tests := []struct {
+		name                 string
+		usage                KeyUsage
+		sign, decrypt, issue bool
+	}{
+		{"sign", UsageSign, true, false, true},
+		{"decrypt", UsageDecrypt, false, true, false},
+		{"cert-sign", UsageCertSign, false, false, true},
+		{"all", UsageSign | UsageDecrypt | UsageCertSign, true, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := NewPolicyKey(key, tt.usage)
+
+			_, err := k.Sign(rand.Reader, digest[:], crypto.SHA256)
+			checkUsage(t, "sign", err, tt.sign)
+			_, err = k.Decrypt(rand.Reader, ciphertext, &rsa.OAEPOptions{Hash: crypto.SHA256})
+			checkUsage(t, "decrypt", err, tt.decrypt)
+			der, err := k.CreateCertificate(template, template, k.Public())
+			checkUsage(t, "certificate", err, tt.issue)
+
+			if tt.issue {
+				cert, err := x509.ParseCertificate(der)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := cert.CheckSignatureFrom(cert); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func checkUsage(t *testing.T, op string, err error, allowed bool) {
+	t.Helper()
+	switch {
+	case allowed && err != nil:
+		t.Fatalf("%s: %v", op, err)
+	case !allowed && !errors.Is(err, ErrKeyUsage):
+		t.Fatalf("%s: expected ErrKeyUsage, got %v", op, err)
+	}
+}
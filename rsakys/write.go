@@ -1,22 +1,28 @@
 package rsakys
 
 import (
+	"bytes"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
-	"os"
+	"fmt"
+	"slices"
+	"strings"
 )
 
-func getPrivateKeyBlock(key *rsa.PrivateKey, format pemFormat) ([]byte, error) {
+const pemLineLength = 64
+
+func getPrivateKeyBlock(key *rsa.PrivateKey, format Format) ([]byte, error) {
 	var block []byte
 
 	switch format {
-	case pkcs1:
+	case PKCS1:
 		block = x509.MarshalPKCS1PrivateKey(key)
 		if len(block) == 0 {
 			return nil, errParse
 		}
-	case pkcs8:
+	case PKCS8:
 		b, err := x509.MarshalPKCS8PrivateKey(key)
 		if err != nil {
 			return nil, err
@@ -29,16 +35,16 @@ func getPrivateKeyBlock(key *rsa.PrivateKey, format pemFormat) ([]byte, error) {
 	return block, nil
 }
 
-func getPublicKeyBlock(key *rsa.PublicKey, format pemFormat) ([]byte, error) {
+func getPublicKeyBlock(key *rsa.PublicKey, format Format) ([]byte, error) {
 	var block []byte
 
 	switch format {
-	case pkcs1:
+	case PKCS1:
 		block = x509.MarshalPKCS1PublicKey(key)
 		if len(block) == 0 {
 			return nil, errParse
 		}
-	case pkix:
+	case PKIX:
 		b, err := x509.MarshalPKIXPublicKey(key)
 		if err != nil {
 			return nil, err
@@ -51,62 +57,174 @@ func getPublicKeyBlock(key *rsa.PublicKey, format pemFormat) ([]byte, error) {
 	return block, nil
 }
 
-func encodePrivateKey(key *rsa.PrivateKey, format pemFormat) ([]byte, error) {
+func privatePEMBlock(key *rsa.PrivateKey, format Format, o *writeOptions) (*pem.Block, error) {
+	if err := o.applyKeyID(&key.PublicKey); err != nil {
+		return nil, err
+	}
+
 	block, err := getPrivateKeyBlock(key, format)
 	if err != nil {
 		return nil, err
 	}
 
-	return pem.EncodeToMemory(&pem.Block{
-		Type:  privateType,
-		Bytes: block,
-	}), nil
+	return &pem.Block{
+		Type:    privateType,
+		Headers: o.headers,
+		Bytes:   block,
+	}, nil
 }
 
-func encodePublicKey(key *rsa.PublicKey, format pemFormat) ([]byte, error) {
+func publicPEMBlock(key *rsa.PublicKey, format Format, o *writeOptions) (*pem.Block, error) {
+	if err := o.applyKeyID(key); err != nil {
+		return nil, err
+	}
+
 	block, err := getPublicKeyBlock(key, format)
 	if err != nil {
 		return nil, err
 	}
 
-	return pem.EncodeToMemory(&pem.Block{
-		Type:  publicType,
-		Bytes: block,
-	}), nil
+	return &pem.Block{
+		Type:    publicType,
+		Headers: o.headers,
+		Bytes:   block,
+	}, nil
 }
 
-func writePrivateKey(path string, key *rsa.PrivateKey, format pemFormat) error {
-	file, err := os.Create(path)
+func encodePrivateKey(key *rsa.PrivateKey, format Format, opts ...WriteOption) ([]byte, error) {
+	o := newWriteOptions(opts)
+	block, err := privatePEMBlock(key, format, o)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer file.Close()
 
-	block, err := getPrivateKeyBlock(key, format)
+	return o.encode(block)
+}
+
+func encodePublicKey(key *rsa.PublicKey, format Format, opts ...WriteOption) ([]byte, error) {
+	o := newWriteOptions(opts)
+	block, err := publicPEMBlock(key, format, o)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := o.encode(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendComment(encoded, o), nil
+}
+
+func writePrivateKey(path string, key *rsa.PrivateKey, format Format, opts ...WriteOption) error {
+	o := newWriteOptions(opts)
+	block, err := privatePEMBlock(key, format, o)
+	if err != nil {
+		return err
+	}
+	encoded, err := o.encode(block)
 	if err != nil {
 		return err
 	}
 
-	return pem.Encode(file, &pem.Block{
-		Type:  privateType,
-		Bytes: block,
-	})
+	if err := writeKeyFile(path, currentConfig().PrivatePerm, encoded, o); err != nil {
+		return err
+	}
+
+	return writeKeyMetadata(path, &key.PublicKey, o)
 }
 
-func writePublicKey(path string, key *rsa.PublicKey, format pemFormat) error {
-	file, err := os.Create(path)
+func writePublicKey(path string, key *rsa.PublicKey, format Format, opts ...WriteOption) error {
+	o := newWriteOptions(opts)
+	block, err := publicPEMBlock(key, format, o)
+	if err != nil {
+		return err
+	}
+	encoded, err := o.encode(block)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	block, err := getPublicKeyBlock(key, format)
+	if err := writeKeyFile(path, currentConfig().PublicPerm, appendComment(encoded, o), o); err != nil {
+		return err
+	}
+
+	return writeKeyMetadata(path, key, o)
+}
+
+// writeKeyMetadata writes the sidecar of a key written with WithMetadata
+func writeKeyMetadata(path string, key *rsa.PublicKey, o *writeOptions) error {
+	if !o.metadata {
+		return nil
+	}
+	md, err := NewMetadata(key, o.usage, o.comment)
 	if err != nil {
 		return err
 	}
 
-	return pem.Encode(file, &pem.Block{
-		Type:  publicType,
-		Bytes: block,
+	return WriteMetadata(path, md)
+}
+
+func appendComment(block []byte, o *writeOptions) []byte {
+	if o.comment == "" {
+		return block
+	}
+
+	return append(block, o.comment+"\n"...)
+}
+
+// encode encodes block with encoding/pem, or canonically if WithDeterministicPEM is set
+func (o *writeOptions) encode(block *pem.Block) ([]byte, error) {
+	if o.deterministic {
+		return encodeDeterministic(block)
+	}
+
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, block); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// encodeDeterministic writes the RFC 7468 strict encoding with RFC 1421 style headers,
+// 'Proc-Type' first as required for encrypted blocks, the others sorted by name
+func encodeDeterministic(block *pem.Block) ([]byte, error) {
+	names := make([]string, 0, len(block.Headers))
+	for k, v := range block.Headers {
+		if strings.ContainsAny(k, ":\r\n") || strings.ContainsAny(v, "\r\n") {
+			return nil, fmt.Errorf("%w: invalid PEM header %q", errParse, k)
+		}
+		names = append(names, k)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "Proc-Type":
+			return -1
+		case b == "Proc-Type":
+			return 1
+		}
+		return strings.Compare(a, b)
 	})
+
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN " + block.Type + "-----\n")
+	for _, k := range names {
+		buf.WriteString(k + ": " + strings.TrimSpace(block.Headers[k]) + "\n")
+	}
+	if len(names) > 0 {
+		buf.WriteByte('\n')
+	}
+
+	b64 := base64.StdEncoding.EncodeToString(block.Bytes)
+	for len(b64) > 0 {
+		n := min(len(b64), pemLineLength)
+		buf.WriteString(b64[:n] + "\n")
+		b64 = b64[n:]
+	}
+	buf.WriteString("-----END " + block.Type + "-----\n")
+
+	return buf.Bytes(), nil
 }